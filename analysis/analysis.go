@@ -0,0 +1,57 @@
+package analysis
+
+import (
+	"github.com/fagnercarvalho/redis-lsp/ast"
+	"github.com/fagnercarvalho/redis-lsp/token"
+)
+
+// Severity uses the same values as the LSP diagnostic severity.
+type Severity int
+
+const (
+	Error       Severity = 1
+	Warning     Severity = 2
+	Information Severity = 3
+	Hint        Severity = 4
+)
+
+// Diagnostic is a problem found in a line of a document.
+// Start and End are the characters in the line where the problem is (End is exclusive).
+type Diagnostic struct {
+	Line     int
+	Start    int
+	End      int
+	Severity Severity
+	Code     string
+	Message  string
+}
+
+// NewDiagnostic returns a diagnostic covering the given node.
+func NewDiagnostic(line int, node ast.Node, severity Severity, code string, message string) Diagnostic {
+	return Diagnostic{
+		Line:     line,
+		Start:    node.LineStart(),
+		End:      node.LineEnd() + 1,
+		Severity: severity,
+		Code:     code,
+		Message:  message,
+	}
+}
+
+// Tokens returns a diagnostic for each token that redis-cli would not be able to split
+// (e.g. a string without the closing quote).
+func Tokens(statements []ast.TokenList) []Diagnostic {
+	var diagnostics []Diagnostic
+	for _, s := range statements {
+		for _, t := range s.GetTokens() {
+			if t.Type() != token.Illegal {
+				continue
+			}
+
+			err := token.IllegalReason(t.String())
+			diagnostics = append(diagnostics, NewDiagnostic(t.Line(), t, Error, "invalid-string", err.Error()))
+		}
+	}
+
+	return diagnostics
+}
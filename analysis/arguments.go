@@ -0,0 +1,253 @@
+package analysis
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/fagnercarvalho/redis-lsp/ast"
+	"github.com/fagnercarvalho/redis-lsp/completer"
+)
+
+// Arguments returns a diagnostic for each argument that does not follow the command syntax:
+// values of the wrong type (e.g. "EXPIRE key abc"), missing values, unknown, repeated or conflicting options and extra arguments.
+// Redis accepts most options in any order, so a known option written in another order than the syntax is not reported,
+// and statements with variables are only checked for the types of the other values.
+func Arguments(statements []ast.TokenList) []Diagnostic {
+	var diagnostics []Diagnostic
+	for _, s := range statements {
+		command := ast.CommandName(s)
+		syntax, ok := completer.CommandArguments(command)
+		if !ok {
+			continue
+		}
+
+		arguments := s.GetArguments()
+		values := make([]string, len(arguments))
+		hasVariables := false
+		for i, a := range arguments {
+			values[i] = a.String()
+			hasVariables = hasVariables || strings.Contains(values[i], "${")
+		}
+
+		if matched := typedMatch(command, syntax, values); matched != nil {
+			diagnostics = append(diagnostics, invalidValues(s, command, syntax, matched, values)...)
+			continue
+		}
+
+		// a comment may hold arguments starting with # (e.g. "SORT key GET #")
+		if hasVariables || ast.GetComment(s) != nil {
+			continue
+		}
+
+		m, ok := typedMismatch(command, syntax, values)
+		if !ok {
+			continue
+		}
+
+		// the unquoted spaces diagnostic already explains the extra arguments unless they look like an option
+		if !unknownOption(syntax, m, values) && len(arguments) >= 2 && len(arguments) <= maxMergedArguments {
+			if _, _, ok := unquotedValue(command, syntax, arguments, values); ok {
+				continue
+			}
+		}
+
+		if d, ok := mismatch(s, command, syntax, m, values); ok {
+			diagnostics = append(diagnostics, d)
+		}
+	}
+
+	return diagnostics
+}
+
+// invalidValues returns a diagnostic for each value that is not of the type of the argument it matched.
+// Options matched by a value they are an alternative of are not checked since the syntax cannot tell them apart (e.g. "ACL LOG [count | RESET]").
+func invalidValues(s ast.TokenList, command string, syntax []*completer.Argument, matched []*completer.Argument, values []string) []Diagnostic {
+	options := map[string]bool{}
+	for _, o := range completer.Options(syntax) {
+		options[o] = true
+	}
+
+	arguments := s.GetArguments()
+
+	var diagnostics []Diagnostic
+	for i, m := range matched {
+		if m.Token || (options[strings.ToUpper(values[i])] && completer.Conflicts(syntax, m.Name, values[i])) {
+			continue
+		}
+
+		// the numkeys of scripts has its own diagnostic
+		if m.Name == "numkeys" && ast.HasNumkeys(s) {
+			continue
+		}
+
+		if validValue(command, m.Name, values[i], values) {
+			continue
+		}
+
+		t := completer.ArgumentType(command, m.Name, values)
+		message := fmt.Sprintf("%v of %v must be %v, got %v", m.Name, command, t, values[i])
+		diagnostics = append(diagnostics, NewDiagnostic(arguments[i].Line(), arguments[i], Error, "invalid-argument", message))
+	}
+
+	return diagnostics
+}
+
+// mismatch returns the diagnostic for the first argument where the statement stops following the syntax.
+func mismatch(s ast.TokenList, command string, syntax []*completer.Argument, m completer.Mismatch, values []string) (Diagnostic, bool) {
+	options := map[string]bool{}
+	for _, o := range completer.Options(syntax) {
+		options[o] = true
+	}
+
+	arguments := s.GetArguments()
+	if m.Index >= len(values) {
+		node := s.GetCommand()
+		if len(arguments) > 0 {
+			node = arguments[len(arguments)-1]
+		}
+
+		message := fmt.Sprintf("missing %v", expectedNames(m.Expected))
+		for i := len(values) - 1; i >= 0; i-- {
+			if options[strings.ToUpper(values[i])] {
+				message = fmt.Sprintf("missing %v after %v", expectedNames(m.Expected), strings.ToUpper(values[i]))
+				break
+			}
+		}
+
+		return NewDiagnostic(ast.EndLine(node), node, Error, "missing-argument", message), true
+	}
+
+	node := arguments[m.Index]
+	value := values[m.Index]
+	option := strings.ToUpper(value)
+
+	if options[option] {
+		for _, previous := range values[:m.Index] {
+			if strings.EqualFold(previous, value) {
+				return NewDiagnostic(node.Line(), node, Warning, "duplicate-option", fmt.Sprintf("%v is repeated", option)), true
+			}
+
+			if options[strings.ToUpper(previous)] && completer.Conflicts(syntax, previous, value) {
+				message := fmt.Sprintf("%v conflicts with %v", option, strings.ToUpper(previous))
+				return NewDiagnostic(node.Line(), node, Error, "conflicting-options", message), true
+			}
+		}
+
+		// an option in another order than the syntax
+		return Diagnostic{}, false
+	}
+
+	if unknownOption(syntax, m, values) || (isWord(value) && onlyTokens(m.Expected)) {
+		message := fmt.Sprintf("unknown option %v for %v, expected %v", value, command, expectedNames(tokens(m.Expected)))
+		return NewDiagnostic(node.Line(), node, Error, "unknown-option", message), true
+	}
+
+	if len(m.Expected) == 0 {
+		return NewDiagnostic(node.Line(), node, Error, "too-many-arguments", fmt.Sprintf("%v has too many arguments", command)), true
+	}
+
+	// a value argument only stops matching a value of the wrong type
+	for _, e := range m.Expected {
+		if !e.Token {
+			t := completer.ArgumentType(command, e.Name, values)
+			message := fmt.Sprintf("%v of %v must be %v, got %v", e.Name, command, t, value)
+			return NewDiagnostic(node.Line(), node, Error, "invalid-argument", message), true
+		}
+	}
+
+	message := fmt.Sprintf("unexpected argument %v, expected %v", value, expectedNames(m.Expected))
+
+	return NewDiagnostic(node.Line(), node, Error, "unexpected-argument", message), true
+}
+
+// expectedNames returns the names of the arguments as a list (e.g. "NX, XX or GET").
+func expectedNames(expected []*completer.Argument) string {
+	var names []string
+	for _, e := range expected {
+		names = append(names, e.Name)
+	}
+
+	switch len(names) {
+	case 0:
+		return "arguments"
+	case 1:
+		return names[0]
+	}
+
+	return strings.Join(names[:len(names)-1], ", ") + " or " + names[len(names)-1]
+}
+
+// typedMatch returns the syntax argument matched by each value, preferring a match where the values are of the type
+// of their argument, or nil when the values do not follow the syntax.
+func typedMatch(command string, syntax []*completer.Argument, values []string) []*completer.Argument {
+	matched := completer.MatchAccepted(syntax, values, func(a *completer.Argument, value string) bool {
+		return validValue(command, a.Name, value, values)
+	})
+	if matched != nil {
+		return matched
+	}
+
+	return completer.Match(syntax, values)
+}
+
+// typedMismatch returns where the values stop following the syntax when values must be of the type of their argument.
+func typedMismatch(command string, syntax []*completer.Argument, values []string) (completer.Mismatch, bool) {
+	return completer.FindMismatch(syntax, values, func(a *completer.Argument, value string) bool {
+		return validValue(command, a.Name, value, values)
+	})
+}
+
+// unknownOption reports whether the statement stops following the syntax at a word in capitals that is not an option
+// where an option can be (e.g. FOO in "SCAN 0 FOO"), which is an unknown option rather than a value.
+func unknownOption(syntax []*completer.Argument, m completer.Mismatch, values []string) bool {
+	if m.Index >= len(values) || len(tokens(m.Expected)) == 0 {
+		return false
+	}
+
+	value := values[m.Index]
+	for _, o := range completer.Options(syntax) {
+		if o == strings.ToUpper(value) {
+			return false
+		}
+	}
+
+	return isWord(value) && strings.ToUpper(value) == value
+}
+
+// tokens returns the literal tokens of the arguments.
+func tokens(arguments []*completer.Argument) []*completer.Argument {
+	var found []*completer.Argument
+	for _, a := range arguments {
+		if a.Token {
+			found = append(found, a)
+		}
+	}
+
+	return found
+}
+
+func onlyTokens(arguments []*completer.Argument) bool {
+	for _, a := range arguments {
+		if !a.Token {
+			return false
+		}
+	}
+
+	return true
+}
+
+// isWord reports whether the value looks like an option (e.g. NOSAVE or WITH-SCORES) instead of a value.
+func isWord(value string) bool {
+	if value == "" || !unicode.IsLetter(rune(value[0])) {
+		return false
+	}
+
+	for _, r := range value {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-' && r != '_' {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,64 @@
+package analysis
+
+import (
+	"testing"
+
+	"github.com/fagnercarvalho/redis-lsp/ast"
+)
+
+func TestArguments(t *testing.T) {
+	tests := []struct {
+		Name          string
+		Text          string
+		ExpectedCodes []string
+	}{
+		{"Valid arguments", "SET k v EX 10", nil},
+		{"Integer", "EXPIRE key abc", []string{"invalid-argument"}},
+		{"Quoted integer", "EXPIRE key \"10\"", nil},
+		{"Infinite scores", "ZADD z +inf a -inf b 1.5 c", nil},
+		{"Invalid score", "ZADD z abc m", []string{"invalid-argument"}},
+		{"Stream IDs", "XADD s 1526919030474-0 f v", nil},
+		{"Invalid stream ID", "XADD s 12x * f v", []string{"invalid-argument"}},
+		{"Special stream IDs", "XREADGROUP GROUP g c STREAMS s >", nil},
+		{"Lex range", "ZRANGEBYLEX z [a (b", nil},
+		{"Invalid lex range", "ZRANGEBYLEX z a +", []string{"invalid-argument"}},
+		{"Range by score", "ZRANGE z (1 +inf BYSCORE", nil},
+		{"Value alternative to an option", "ACL LOG RESET", nil},
+		{"Unknown option after integer", "EXPIRE k 10 FOO", []string{"unknown-option"}},
+		{"Unknown option after cursor", "SCAN 0 FOO", []string{"unknown-option"}},
+		{"Unknown option before score", "ZADD z FOO 1 a", []string{"unknown-option"}},
+		{"Unknown option after value", "SET k v FOO", []string{"unknown-option"}},
+		{"Conflicting options", "SET k v NX XX", []string{"conflicting-options"}},
+		{"Conflicting options with values", "SET k v EX 10 PX 5", []string{"conflicting-options"}},
+		{"Repeated option", "SET k v EX 10 EX 5", []string{"duplicate-option"}},
+		{"Options in another order", "SET k v EX 10 NX", nil},
+		{"Missing value after option", "ZRANGEBYSCORE z 0 1 LIMIT 0", []string{"missing-argument"}},
+		{"Missing required argument", "GET", []string{"missing-argument"}},
+		{"Unquoted value", "SET greeting hello world", nil},
+		{"Missing member", "ZADD z 1 a 2", []string{"missing-argument"}},
+		{"Missing value", "MSET a 1 b", []string{"missing-argument"}},
+		{"Variables", "EXPIRE k ${ttl}", nil},
+		{"Repeated subcommands", "BITFIELD k SET u1 2 1 SET u1 3 1", nil},
+		{"Option after repeated values", "XCLAIM s g c 0 1-1 JUSTID", nil},
+		{"Flag after repeated values", "XCLAIM s g c 0 1-1 FORCE", nil},
+		{"Option with value after repeated values", "XCLAIM s g c 0 1-1 IDLE 5", nil},
+		{"Stream ID option after repeated values", "XCLAIM s g c 0 1-1 LASTID 2-2", nil},
+		{"Options after repeated values", "XCLAIM s g c 0 1-1 RETRYCOUNT 3 JUSTID", nil},
+		{"Invalid repeated value", "XCLAIM s g c 0 1-1 abc", []string{"invalid-argument"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			diagnostics := Arguments(ast.Parse(test.Text))
+			if len(diagnostics) != len(test.ExpectedCodes) {
+				t.Fatalf("%v - Unexpected diagnostics: %v (expected %v)", test.Name, diagnostics, test.ExpectedCodes)
+			}
+
+			for i, d := range diagnostics {
+				if d.Code != test.ExpectedCodes[i] {
+					t.Errorf("%v - Unexpected code: %v (expected %v)", test.Name, d.Code, test.ExpectedCodes[i])
+				}
+			}
+		})
+	}
+}
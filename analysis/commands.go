@@ -0,0 +1,222 @@
+package analysis
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fagnercarvalho/redis-lsp/ast"
+	"github.com/fagnercarvalho/redis-lsp/completer"
+	"github.com/fagnercarvalho/redis-lsp/token"
+	"github.com/fagnercarvalho/redis-lsp/variables"
+)
+
+// maxMergedArguments limits the arguments of the statements checked for unquoted values
+// since every pair of arguments is tried.
+const maxMergedArguments = 16
+
+// UnknownCommands returns a diagnostic for each command or subcommand that is not in the command catalogue
+// with the most similar command when it looks like a typo (e.g. GTE).
+// Module commands (e.g. JSON.GET), commands written with variables and @set directives are not checked.
+func UnknownCommands(statements []ast.TokenList) []Diagnostic {
+	var diagnostics []Diagnostic
+	for _, s := range statements {
+		node := s.GetCommand()
+		if node == nil || (node.Type() != token.Keyword && node.Type() != token.Unknown) || variables.IsDirective(s) {
+			continue
+		}
+
+		command := ast.CommandName(s)
+		if strings.ContainsAny(command, ".$") {
+			continue
+		}
+
+		if _, ok := completer.CommandSince(command); ok {
+			continue
+		}
+
+		// DEBUG has many subcommands that are not documented
+		if completer.IsContainer(command) {
+			arguments := s.GetArguments()
+			if command == "DEBUG" || len(arguments) == 0 {
+				continue
+			}
+
+			node = arguments[0]
+			command = command + " " + strings.ToUpper(node.String())
+		}
+
+		message := fmt.Sprintf("unknown command %v", command)
+		if similar, ok := completer.SimilarCommand(command); ok {
+			message = fmt.Sprintf("unknown command %v, did you mean %v?", command, similar)
+		}
+
+		diagnostics = append(diagnostics, NewDiagnostic(node.Line(), node, Warning, "unknown-command", message))
+	}
+
+	return diagnostics
+}
+
+// UnclosedTransactions returns a diagnostic for a MULTI without an EXEC or DISCARD after it.
+func UnclosedTransactions(statements []ast.TokenList) []Diagnostic {
+	var open ast.Node
+	for _, s := range statements {
+		switch ast.CommandName(s) {
+		case "MULTI":
+			if open == nil {
+				open = s.GetCommand()
+			}
+		case "EXEC", "DISCARD":
+			open = nil
+		}
+	}
+
+	if open == nil {
+		return nil
+	}
+
+	return []Diagnostic{NewDiagnostic(open.Line(), open, Error, "unclosed-multi", "MULTI without EXEC or DISCARD")}
+}
+
+// UnquotedSpaces returns a diagnostic for the statements with more arguments than the command accepts
+// that would follow its syntax if some consecutive arguments were a single quoted value (e.g. "SET greeting hello world").
+func UnquotedSpaces(statements []ast.TokenList) []Diagnostic {
+	var diagnostics []Diagnostic
+	for _, s := range statements {
+		command := ast.CommandName(s)
+		syntax, ok := completer.CommandArguments(command)
+		if !ok {
+			continue
+		}
+
+		arguments := s.GetArguments()
+		if len(arguments) < 2 || len(arguments) > maxMergedArguments {
+			continue
+		}
+
+		values := make([]string, len(arguments))
+		for i, a := range arguments {
+			values[i] = a.String()
+		}
+
+		if completer.Match(syntax, values) != nil {
+			continue
+		}
+
+		// a word in capitals where an option can be is an unknown option (e.g. "SET k v FOO")
+		if m, ok := typedMismatch(command, syntax, values); ok && unknownOption(syntax, m, values) {
+			continue
+		}
+
+		first, last, ok := unquotedValue(command, syntax, arguments, values)
+		if !ok {
+			continue
+		}
+
+		value := statementText(s, arguments[first], arguments[last])
+		message := fmt.Sprintf("%v has too many arguments, \"%v\" should be quoted if it is a single value", command, value)
+		diagnostics = append(diagnostics, Diagnostic{
+			Line:     arguments[first].Line(),
+			Start:    arguments[first].LineStart(),
+			End:      arguments[last].LineEnd() + 1,
+			Severity: Warning,
+			Code:     "unquoted-spaces",
+			Message:  message,
+		})
+	}
+
+	return diagnostics
+}
+
+// unquotedValue returns the first and last of the consecutive unquoted arguments in the same line
+// that follow the syntax when they are a single value, the last arguments are tried first since keys rarely have spaces.
+// Options of the command are not merged nor used as values since they are more likely misplaced (e.g. "SET k v NX XX"),
+// and when the first merge that follows the syntax has a value of the wrong type (e.g. "10 FOO" as the seconds of EXPIRE)
+// the arguments are wrong instead of unquoted.
+// Statements that are missing an argument (e.g. "ZADD z 1 a 2") are left to the missing argument diagnostic
+// since quoting would change what they do.
+func unquotedValue(command string, syntax []*completer.Argument, arguments []ast.Node, values []string) (int, int, bool) {
+	if m, ok := completer.FindMismatch(syntax, values, nil); ok && m.Index >= len(values) {
+		return 0, 0, false
+	}
+
+	options := map[string]bool{}
+	for _, o := range completer.Options(syntax) {
+		options[o] = true
+	}
+
+	for first := len(arguments) - 2; first >= 0; first-- {
+		if arguments[first].Type() == token.String || options[strings.ToUpper(values[first])] {
+			continue
+		}
+
+		for last := first + 1; last < len(arguments); last++ {
+			if arguments[last].Type() == token.String || arguments[last].Line() != arguments[first].Line() || options[strings.ToUpper(values[last])] {
+				break
+			}
+
+			merged := append(append(append([]string{}, values[:first]...), strings.Join(values[first:last+1], " ")), values[last+1:]...)
+			matched := completer.Match(syntax, merged)
+			if matched == nil || matched[first].Token || optionAsValue(options, matched, merged) {
+				continue
+			}
+
+			return first, last, validValues(command, matched, merged)
+		}
+	}
+
+	return 0, 0, false
+}
+
+// optionAsValue reports whether an option of the command was matched as a value.
+func optionAsValue(options map[string]bool, matched []*completer.Argument, values []string) bool {
+	for i, m := range matched {
+		if !m.Token && options[strings.ToUpper(values[i])] {
+			return true
+		}
+	}
+
+	return false
+}
+
+// validValues reports whether each value matched by a value argument is of the type of the argument,
+// values with variables and values that cannot be unquoted are not checked.
+func validValues(command string, matched []*completer.Argument, values []string) bool {
+	for i, m := range matched {
+		if !m.Token && !validValue(command, m.Name, values[i], values) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// validValue reports whether the value is of the type of the argument of the command, values are the arguments of the statement.
+func validValue(command string, name string, value string, values []string) bool {
+	unquoted, err := token.Unquote(value)
+	if err != nil || strings.Contains(value, "${") {
+		return true
+	}
+
+	return completer.ValidValue(completer.ArgumentType(command, name, values), unquoted)
+}
+
+// statementText returns the text of the statement from the first node to the last one, including the spaces between them.
+func statementText(s ast.TokenList, first ast.Node, last ast.Node) string {
+	var text strings.Builder
+	inside := false
+	for _, t := range s.GetTokens() {
+		if t.Start() == first.Start() {
+			inside = true
+		}
+
+		if inside {
+			text.WriteString(t.String())
+		}
+
+		if t.Start() == last.Start() {
+			break
+		}
+	}
+
+	return text.String()
+}
@@ -0,0 +1,69 @@
+package analysis
+
+import (
+	"testing"
+
+	"github.com/fagnercarvalho/redis-lsp/ast"
+)
+
+func TestUnknownCommands(t *testing.T) {
+	tests := []struct {
+		Name             string
+		Text             string
+		ExpectedMessages []string
+	}{
+		{"Known command", "GET a", nil},
+		{"Misspelled command", "GTE a", []string{"unknown command GTE, did you mean GET?"}},
+		{"Misspelled subcommand", "CONFIG GTE maxmemory", []string{"unknown command CONFIG GTE, did you mean CONFIG GET?"}},
+		{"Module command", "JSON.GET a", nil},
+		{"Variable directive", "@set a = 1\nGET ${a}", nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			diagnostics := UnknownCommands(ast.Parse(test.Text))
+			if len(diagnostics) != len(test.ExpectedMessages) {
+				t.Fatalf("%v - Unexpected diagnostics: %v (expected %v)", test.Name, diagnostics, test.ExpectedMessages)
+			}
+
+			for i, d := range diagnostics {
+				if d.Message != test.ExpectedMessages[i] {
+					t.Errorf("%v - Unexpected message: %v (expected %v)", test.Name, d.Message, test.ExpectedMessages[i])
+				}
+			}
+		})
+	}
+}
+
+func TestUnquotedSpaces(t *testing.T) {
+	tests := []struct {
+		Name             string
+		Text             string
+		ExpectedMessages []string
+	}{
+		{"Value with spaces", "SET greeting hello world", []string{"SET has too many arguments, \"hello world\" should be quoted if it is a single value"}},
+		{"Value with spaces before an option", "SET greeting hello world EX 10", []string{"SET has too many arguments, \"hello world\" should be quoted if it is a single value"}},
+		{"Quoted value", "SET greeting \"hello world\"", nil},
+		{"Misplaced option", "SET k v NX XX", nil},
+		{"Integer and unknown option", "EXPIRE k 10 FOO", nil},
+		{"Cursor and unknown option", "SCAN 0 FOO", nil},
+		{"Invalid stream ID", "XADD s 12x * f v", nil},
+		{"Missing member", "ZADD z 1 a 2", nil},
+		{"Missing value", "MSET a 1 b", nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			diagnostics := UnquotedSpaces(ast.Parse(test.Text))
+			if len(diagnostics) != len(test.ExpectedMessages) {
+				t.Fatalf("%v - Unexpected diagnostics: %v (expected %v)", test.Name, diagnostics, test.ExpectedMessages)
+			}
+
+			for i, d := range diagnostics {
+				if d.Message != test.ExpectedMessages[i] {
+					t.Errorf("%v - Unexpected message: %v (expected %v)", test.Name, d.Message, test.ExpectedMessages[i])
+				}
+			}
+		})
+	}
+}
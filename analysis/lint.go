@@ -0,0 +1,140 @@
+package analysis
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/fagnercarvalho/redis-lsp/ast"
+)
+
+// Cardinality returns the number of elements of a key on the server, ok is false when it could not be fetched.
+type Cardinality func(key string) (int64, bool)
+
+// fullCollections are the commands that return a whole collection with the command to read it in parts.
+var fullCollections = map[string]string{
+	"HGETALL":  "HSCAN",
+	"SMEMBERS": "SSCAN",
+}
+
+// blockingTimeouts are the index of the timeout of the blocking commands, -1 is the last argument.
+var blockingTimeouts = map[string]int{
+	"BLMOVE":     -1,
+	"BLMPOP":     0,
+	"BLPOP":      -1,
+	"BRPOP":      -1,
+	"BRPOPLPUSH": -1,
+	"BZMPOP":     0,
+	"BZPOPMAX":   -1,
+	"BZPOPMIN":   -1,
+	"WAIT":       -1,
+	"WAITAOF":    -1,
+}
+
+// Lint returns a warning for each statement that can hurt a production server (e.g. KEYS or FLUSHALL without ASYNC).
+// When cardinality is not nil the rules about big keys are only reported for keys with at least largeKey elements,
+// otherwise they are reported for every key except for DEL, which is only reported for keys known to be big.
+func Lint(statements []ast.TokenList, cardinality Cardinality, largeKey int64) []Diagnostic {
+	// big reports whether the key may be big and the message that says how big it is
+	big := func(key string) (bool, string) {
+		if cardinality == nil || strings.Contains(key, "${") {
+			return true, ""
+		}
+
+		n, ok := cardinality(key)
+		if !ok {
+			return true, ""
+		}
+
+		return n >= largeKey, fmt.Sprintf(" (%v has %v elements)", key, n)
+	}
+
+	var diagnostics []Diagnostic
+	add := func(node ast.Node, code string, message string) {
+		diagnostics = append(diagnostics, NewDiagnostic(node.Line(), node, Warning, code, message))
+	}
+
+	for _, s := range statements {
+		command := ast.CommandName(s)
+		node := s.GetCommand()
+		arguments := s.GetArguments()
+
+		switch command {
+		case "KEYS":
+			if len(arguments) == 1 && strings.ContainsAny(arguments[0].String(), "*?[") {
+				add(node, "keys-wildcard", "KEYS blocks the server while it goes through every key, use SCAN with MATCH instead")
+			}
+		case "FLUSHALL", "FLUSHDB":
+			if !hasOption(arguments, "ASYNC") {
+				add(node, "flush-sync", fmt.Sprintf("%v blocks the server until every key is deleted, add ASYNC to delete them in the background", command))
+			}
+		case "HGETALL", "SMEMBERS", "LRANGE":
+			if len(arguments) == 0 {
+				continue
+			}
+
+			message := fmt.Sprintf("%v returns the whole collection at once, use %v for big collections", command, fullCollections[command])
+			if command == "LRANGE" {
+				if len(arguments) != 3 || arguments[1].String() != "0" || arguments[2].String() != "-1" {
+					continue
+				}
+
+				message = "LRANGE 0 -1 returns the whole list at once, read big lists in ranges"
+			}
+
+			if isBig, size := big(arguments[0].String()); isBig {
+				add(node, "full-collection", message+size)
+			}
+		case "DEL":
+			if cardinality == nil {
+				continue
+			}
+
+			for _, key := range arguments {
+				if isBig, size := big(key.String()); isBig && size != "" {
+					add(key, "del-large-key", fmt.Sprintf("DEL blocks the server while a big key is freed, UNLINK frees it in the background%v", size))
+				}
+			}
+		case "SORT", "SORT_RO":
+			if len(arguments) > 0 && !hasOption(arguments, "LIMIT") {
+				add(node, "sort-without-limit", fmt.Sprintf("%v without LIMIT sorts and returns every element, add LIMIT offset count", command))
+			}
+		case "XREAD", "XREADGROUP":
+			for i, a := range arguments {
+				if strings.EqualFold(a.String(), "BLOCK") && i+1 < len(arguments) && isZero(arguments[i+1].String()) {
+					add(arguments[i+1], "blocking-forever", fmt.Sprintf("%v with BLOCK 0 waits forever for new entries, use a timeout", command))
+				}
+			}
+		default:
+			index, ok := blockingTimeouts[command]
+			if !ok || len(arguments) == 0 {
+				continue
+			}
+
+			if index < 0 {
+				index = len(arguments) + index
+			}
+
+			if index < len(arguments) && isZero(arguments[index].String()) {
+				add(arguments[index], "blocking-forever", fmt.Sprintf("%v with timeout 0 blocks the connection forever, use a timeout", command))
+			}
+		}
+	}
+
+	return diagnostics
+}
+
+func hasOption(arguments []ast.Node, option string) bool {
+	for _, a := range arguments {
+		if strings.EqualFold(a.String(), option) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isZero(value string) bool {
+	n, err := strconv.ParseFloat(value, 64)
+	return err == nil && n == 0
+}
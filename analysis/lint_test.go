@@ -0,0 +1,59 @@
+package analysis
+
+import (
+	"testing"
+
+	"github.com/fagnercarvalho/redis-lsp/ast"
+)
+
+func TestLint(t *testing.T) {
+	sizes := map[string]int64{"big": 5000, "small": 10}
+	cardinality := func(key string) (int64, bool) {
+		n, ok := sizes[key]
+		return n, ok
+	}
+
+	tests := []struct {
+		Name          string
+		Text          string
+		Cardinality   Cardinality
+		ExpectedCodes []string
+	}{
+		{"KEYS with wildcard", "KEYS user:*", nil, []string{"keys-wildcard"}},
+		{"KEYS without wildcard", "KEYS user:1", nil, nil},
+		{"FLUSHALL", "FLUSHALL", nil, []string{"flush-sync"}},
+		{"FLUSHDB ASYNC", "flushdb async", nil, nil},
+		{"HGETALL", "HGETALL user:1", nil, []string{"full-collection"}},
+		{"SMEMBERS of a small set", "SMEMBERS small", cardinality, nil},
+		{"SMEMBERS of a big set", "SMEMBERS big", cardinality, []string{"full-collection"}},
+		{"SMEMBERS of an unknown set", "SMEMBERS other", cardinality, []string{"full-collection"}},
+		{"Whole list", "LRANGE list 0 -1", nil, []string{"full-collection"}},
+		{"List range", "LRANGE list 0 10", nil, nil},
+		{"DEL without cardinality", "DEL big", nil, nil},
+		{"DEL of a big key", "DEL small big", cardinality, []string{"del-large-key"}},
+		{"SORT without LIMIT", "SORT list", nil, []string{"sort-without-limit"}},
+		{"SORT with LIMIT", "SORT list LIMIT 0 10", nil, nil},
+		{"Timeout as last argument", "BLPOP list 0", nil, []string{"blocking-forever"}},
+		{"Timeout with decimals", "BRPOP list 0.0", nil, []string{"blocking-forever"}},
+		{"BLMPOP timeout", "BLMPOP 0 1 list LEFT", nil, []string{"blocking-forever"}},
+		{"BZMPOP timeout", "BZMPOP 0 1 zset MIN", nil, []string{"blocking-forever"}},
+		{"BLMPOP with timeout", "BLMPOP 5 1 list LEFT", nil, nil},
+		{"XREAD BLOCK 0", "XREAD BLOCK 0 STREAMS s $", nil, []string{"blocking-forever"}},
+		{"XREAD with timeout", "XREAD BLOCK 100 STREAMS s $", nil, nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			diagnostics := Lint(ast.Parse(test.Text), test.Cardinality, 1000)
+			if len(diagnostics) != len(test.ExpectedCodes) {
+				t.Fatalf("%v - Unexpected diagnostics: %v (expected %v)", test.Name, diagnostics, test.ExpectedCodes)
+			}
+
+			for i, d := range diagnostics {
+				if d.Code != test.ExpectedCodes[i] {
+					t.Errorf("%v - Unexpected code: %v (expected %v)", test.Name, d.Code, test.ExpectedCodes[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,236 @@
+package analysis
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/fagnercarvalho/redis-lsp/ast"
+	"github.com/fagnercarvalho/redis-lsp/lua"
+	"github.com/fagnercarvalho/redis-lsp/token"
+	"github.com/fagnercarvalho/redis-lsp/variables"
+)
+
+// Scripts returns a diagnostic for the Lua syntax errors in the scripts of EVAL, EVAL_RO and SCRIPT LOAD statements.
+// Scripts with ${NAME} placeholders are not checked since the script is only known after they are replaced.
+func Scripts(statements []ast.TokenList) []Diagnostic {
+	var diagnostics []Diagnostic
+	for _, s := range statements {
+		script := ast.GetScript(s)
+		if script == nil || script.Type() != token.String {
+			continue
+		}
+
+		if !strings.HasPrefix(script.String(), "'") && len(variables.References(script.String())) > 0 {
+			continue
+		}
+
+		source, offsets, err := token.UnquoteOffsets(script.String())
+		if err != nil {
+			continue
+		}
+
+		var syntaxError *lua.Error
+		if !errors.As(lua.Check(source), &syntaxError) {
+			continue
+		}
+
+		diagnostics = append(diagnostics, scriptDiagnostic(script, offsets, syntaxError.Start, syntaxError.End, Error, "lua-syntax", syntaxError.Message))
+	}
+
+	return diagnostics
+}
+
+// Numkeys returns a diagnostic when the numkeys of an EVAL or EVALSHA statement is not a non-negative integer
+// or is greater than the number of arguments, and for each KEYS[n] or ARGV[n] in the script that is not passed to it.
+func Numkeys(statements []ast.TokenList) []Diagnostic {
+	var diagnostics []Diagnostic
+	for _, s := range statements {
+		if !ast.HasNumkeys(s) {
+			continue
+		}
+
+		command := ast.CommandName(s)
+		arguments := s.GetArguments()
+		if len(arguments) < 2 {
+			node := s.GetCommand()
+			if len(arguments) == 1 {
+				node = arguments[0]
+			}
+
+			message := fmt.Sprintf("%v requires numkeys after the script", command)
+			if command == "FCALL" || command == "FCALL_RO" {
+				message = fmt.Sprintf("%v requires numkeys after the function", command)
+			}
+
+			diagnostics = append(diagnostics, NewDiagnostic(node.Line(), node, Error, "invalid-numkeys", message))
+			continue
+		}
+
+		numkeys, ok := ast.Numkeys(arguments[1])
+		if !ok {
+			message := fmt.Sprintf("numkeys must be a non-negative integer but is %v", arguments[1].String())
+			diagnostics = append(diagnostics, NewDiagnostic(arguments[1].Line(), arguments[1], Error, "invalid-numkeys", message))
+			continue
+		}
+
+		given := len(arguments) - 2
+		if numkeys > given {
+			message := fmt.Sprintf("numkeys is %v but %v given", numkeys, count(given, "key is", "keys are"))
+			diagnostics = append(diagnostics, NewDiagnostic(arguments[1].Line(), arguments[1], Error, "numkeys-mismatch", message))
+			continue
+		}
+
+		diagnostics = append(diagnostics, scriptIndexes(s, numkeys, given-numkeys)...)
+	}
+
+	return diagnostics
+}
+
+// scriptIndexes returns a diagnostic for each KEYS[n] and ARGV[n] in the script of the statement that is not passed to it.
+func scriptIndexes(s ast.TokenList, keys int, arguments int) []Diagnostic {
+	script := ast.GetScript(s)
+	if script == nil || script.Type() != token.String {
+		return nil
+	}
+
+	if !strings.HasPrefix(script.String(), "'") && len(variables.References(script.String())) > 0 {
+		return nil
+	}
+
+	source, offsets, err := token.UnquoteOffsets(script.String())
+	if err != nil {
+		return nil
+	}
+
+	var diagnostics []Diagnostic
+	for _, index := range lua.Indexes(source) {
+		if message, ok := undefinedIndex(index, keys, arguments, "passed to the script"); ok {
+			diagnostics = append(diagnostics, scriptDiagnostic(script, offsets, index.Start, index.End, Warning, "script-index", message))
+		}
+	}
+
+	return diagnostics
+}
+
+// ScriptFile returns the Lua syntax errors of a script file and, when the header of the script declares its keys and arguments,
+// a diagnostic for each KEYS[n] and ARGV[n] that is not declared.
+// Function libraries must have a name and register at least one function to be loaded.
+func ScriptFile(source string) []Diagnostic {
+	var diagnostics []Diagnostic
+
+	var syntaxError *lua.Error
+	if errors.As(lua.Check(source), &syntaxError) {
+		diagnostics = append(diagnostics, sourceDiagnostic(source, syntaxError.Start, syntaxError.End, Error, "lua-syntax", syntaxError.Message))
+	}
+
+	if library, ok := lua.ParseLibrary(source); ok {
+		end := len(source)
+		if newline := strings.IndexByte(source, '\n'); newline != -1 {
+			end = newline
+		}
+
+		if library.Name == "" {
+			diagnostics = append(diagnostics, sourceDiagnostic(source, 0, end, Error, "library", "library name is missing (e.g. #!lua name=mylib)"))
+		} else if len(library.Functions) == 0 {
+			diagnostics = append(diagnostics, sourceDiagnostic(source, 0, end, Warning, "library", "the library does not register any function with redis.register_function"))
+		}
+
+		return diagnostics
+	}
+
+	header := lua.ParseHeader(source)
+	if !header.Declared {
+		return diagnostics
+	}
+
+	for _, index := range lua.Indexes(source) {
+		if message, ok := undefinedIndex(index, len(header.Keys), len(header.Arguments), "declared in the header"); ok {
+			diagnostics = append(diagnostics, sourceDiagnostic(source, index.Start, index.End, Warning, "script-index", message))
+		}
+	}
+
+	return diagnostics
+}
+
+// undefinedIndex returns why a KEYS[n] or ARGV[n] is nil when n is not between 1 and the number of keys or arguments.
+func undefinedIndex(index lua.Index, keys int, arguments int, where string) (string, bool) {
+	if index.Name == "ARGV" {
+		if index.Number >= 1 && index.Number <= arguments {
+			return "", false
+		}
+
+		return fmt.Sprintf("ARGV[%v] is nil since %v %v", index.Number, count(arguments, "argument is", "arguments are"), where), true
+	}
+
+	if index.Number >= 1 && index.Number <= keys {
+		return "", false
+	}
+
+	return fmt.Sprintf("KEYS[%v] is nil since %v %v", index.Number, count(keys, "key is", "keys are"), where), true
+}
+
+// count returns the number followed by the singular or plural noun (e.g. "1 key is" or "2 keys are").
+func count(n int, singular string, plural string) string {
+	switch n {
+	case 0:
+		return "no " + plural
+	case 1:
+		return "1 " + singular
+	}
+
+	return fmt.Sprintf("%v %v", n, plural)
+}
+
+// scriptDiagnostic returns a diagnostic for the part of a script between the start and end offsets (end is exclusive),
+// offsets maps the script offsets to the token offsets as returned by token.UnquoteOffsets.
+// The diagnostic ends at the end of the line when the part has line breaks and has at least one character.
+func scriptDiagnostic(script ast.Node, offsets []int, start int, end int, severity Severity, code string, message string) Diagnostic {
+	line, startCharacter := ast.OffsetPosition(script, offsets[start])
+	endLine, endCharacter := ast.OffsetPosition(script, offsets[end])
+
+	if endLine != line {
+		value := script.String()[offsets[start]:]
+		endCharacter = startCharacter + len(value)
+		if newline := strings.IndexByte(value, '\n'); newline != -1 {
+			endCharacter = startCharacter + newline
+		}
+	}
+
+	if endCharacter <= startCharacter {
+		endCharacter = startCharacter + 1
+	}
+
+	return Diagnostic{
+		Line:     line,
+		Start:    startCharacter,
+		End:      endCharacter,
+		Severity: severity,
+		Code:     code,
+		Message:  message,
+	}
+}
+
+// sourceDiagnostic returns a diagnostic for the part of a script file between the start and end offsets (end is exclusive).
+// The diagnostic ends at the end of the line when the part has line breaks and has at least one character.
+func sourceDiagnostic(source string, start int, end int, severity Severity, code string, message string) Diagnostic {
+	line := strings.Count(source[:start], "\n")
+	lineStart := strings.LastIndexByte(source[:start], '\n') + 1
+
+	if newline := strings.IndexByte(source[start:end], '\n'); newline != -1 {
+		end = start + newline
+	}
+
+	if end <= start {
+		end = start + 1
+	}
+
+	return Diagnostic{
+		Line:     line,
+		Start:    start - lineStart,
+		End:      end - lineStart,
+		Severity: severity,
+		Code:     code,
+		Message:  message,
+	}
+}
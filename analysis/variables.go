@@ -0,0 +1,48 @@
+package analysis
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fagnercarvalho/redis-lsp/ast"
+	"github.com/fagnercarvalho/redis-lsp/variables"
+)
+
+// Variables returns a diagnostic for each ${NAME} placeholder that can not be resolved.
+// Single quoted strings are not checked since placeholders are not replaced inside them.
+func Variables(statements []ast.TokenList, resolver variables.Resolver) []Diagnostic {
+	var diagnostics []Diagnostic
+	for _, s := range statements {
+		var nodes []ast.Node
+		if command := s.GetCommand(); command != nil {
+			nodes = append(nodes, command)
+		}
+
+		nodes = append(nodes, s.GetArguments()...)
+
+		for _, n := range nodes {
+			if strings.HasPrefix(n.String(), "'") {
+				continue
+			}
+
+			for _, r := range variables.References(n.String()) {
+				if _, ok := resolver.Lookup(r.Name, s.Line()); ok {
+					continue
+				}
+
+				// the string may have line breaks before the placeholder
+				line, start := ast.OffsetPosition(n, r.Start)
+				diagnostics = append(diagnostics, Diagnostic{
+					Line:     line,
+					Start:    start,
+					End:      start + r.End - r.Start,
+					Severity: Error,
+					Code:     "unresolved-variable",
+					Message:  fmt.Sprintf("variable %v is not defined", r.Name),
+				})
+			}
+		}
+	}
+
+	return diagnostics
+}
@@ -0,0 +1,78 @@
+package analysis
+
+import (
+	"fmt"
+
+	"github.com/fagnercarvalho/redis-lsp/ast"
+	"github.com/fagnercarvalho/redis-lsp/completer"
+)
+
+// Versions returns a diagnostic for each command or option that is not available in the target Redis version
+// and for each deprecated command with what replaces it.
+// Without a target version every command is available and every deprecated command is reported.
+func Versions(statements []ast.TokenList, target string) []Diagnostic {
+	var diagnostics []Diagnostic
+	for _, s := range statements {
+		node := s.GetCommand()
+		if node == nil {
+			continue
+		}
+
+		command := ast.CommandName(s)
+		since, ok := completer.CommandSince(command)
+		if !ok {
+			continue
+		}
+
+		if !completer.Available(since, target) {
+			message := fmt.Sprintf("%v is not available in Redis %v (since %v)", command, target, since)
+			diagnostics = append(diagnostics, NewDiagnostic(node.Line(), node, Error, "unsupported-command", message))
+			continue
+		}
+
+		if d, ok := completer.Deprecated(command); ok && completer.Available(d.Since, target) {
+			message := fmt.Sprintf("%v is deprecated since Redis %v, use %v instead", command, d.Since, d.Replacement)
+			diagnostics = append(diagnostics, NewDiagnostic(node.Line(), node, Warning, "deprecated-command", message))
+		}
+
+		diagnostics = append(diagnostics, unsupportedOptions(s, command, target)...)
+	}
+
+	return diagnostics
+}
+
+// unsupportedOptions returns a diagnostic for each option of the statement added to the command after the target version.
+// Only the arguments that follow the command syntax are checked so a value that looks like an option (e.g. the "GET" in "SET GET value") is not reported.
+func unsupportedOptions(s ast.TokenList, command string, target string) []Diagnostic {
+	if target == "" {
+		return nil
+	}
+
+	syntax, ok := completer.CommandArguments(command)
+	if !ok {
+		return nil
+	}
+
+	arguments := s.GetArguments()
+	values := make([]string, len(arguments))
+	for i, a := range arguments {
+		values[i] = a.String()
+	}
+
+	var diagnostics []Diagnostic
+	for i, matched := range completer.Match(syntax, values) {
+		if matched == nil || !matched.Token {
+			continue
+		}
+
+		since, ok := completer.OptionSince(command, matched.Name)
+		if !ok || completer.Available(since, target) {
+			continue
+		}
+
+		message := fmt.Sprintf("%v %v is not available in Redis %v (since %v)", command, matched.Name, target, since)
+		diagnostics = append(diagnostics, NewDiagnostic(arguments[i].Line(), arguments[i], Error, "unsupported-option", message))
+	}
+
+	return diagnostics
+}
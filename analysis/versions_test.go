@@ -0,0 +1,42 @@
+package analysis
+
+import (
+	"testing"
+
+	"github.com/fagnercarvalho/redis-lsp/ast"
+)
+
+func TestVersions(t *testing.T) {
+	tests := []struct {
+		Name             string
+		Text             string
+		Target           string
+		ExpectedMessages []string
+	}{
+		{"Command too new", "GETEX k EX 10", "6.0", []string{"GETEX is not available in Redis 6.0 (since 6.2.0)"}},
+		{"Command available", "GETEX k EX 10", "6.2", nil},
+		{"Option too new", "SET k v GET", "6.0", []string{"SET GET is not available in Redis 6.0 (since 6.2.0)"}},
+		{"Option with value too new", "SET k v EXAT 1700000000", "6.0", []string{"SET EXAT is not available in Redis 6.0 (since 6.2.0)"}},
+		{"Option available", "SET k v GET", "7.0", nil},
+		{"Options without target", "SET k v EXAT 1700000000", "", nil},
+		{"Deprecated without target", "HMSET h f v", "", []string{"HMSET is deprecated since Redis 4.0.0, use HSET instead"}},
+		{"Deprecated in target", "HMSET h f v", "6.0", []string{"HMSET is deprecated since Redis 4.0.0, use HSET instead"}},
+		{"Not yet deprecated in target", "GEORADIUS geo 15 37 200 km", "6.0", nil},
+		{"Deprecated before target", "GEORADIUS geo 15 37 200 km", "7.2", []string{"GEORADIUS is deprecated since Redis 6.2.0, use GEOSEARCH or GEOSEARCHSTORE with BYRADIUS instead"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			diagnostics := Versions(ast.Parse(test.Text), test.Target)
+			if len(diagnostics) != len(test.ExpectedMessages) {
+				t.Fatalf("%v - Unexpected diagnostics: %v (expected %v)", test.Name, diagnostics, test.ExpectedMessages)
+			}
+
+			for i, d := range diagnostics {
+				if d.Message != test.ExpectedMessages[i] {
+					t.Errorf("%v - Unexpected message: %v (expected %v)", test.Name, d.Message, test.ExpectedMessages[i])
+				}
+			}
+		})
+	}
+}
@@ -10,6 +10,8 @@ type TokenList interface {
 	GetTokens() []Node
 	AddToken(Node)
 	SetTokens([]Node)
+	GetCommand() Node
+	GetArguments() []Node
 	NextTokenIs([]string, int) bool
 	PreviousTokenIs([]string, int) bool
 }
@@ -41,6 +43,38 @@ func (s *Statement) GetTokens() []Node {
 	return s.Tokens
 }
 
+// GetCommand returns the first meaningful token of the statement, which is either a keyword or a multi keyword.
+// It returns nil if the statement has no command (e.g. an empty line).
+func (s *Statement) GetCommand() Node {
+	for _, t := range s.Tokens {
+		if isIgnored(t) {
+			continue
+		}
+
+		return t
+	}
+
+	return nil
+}
+
+// GetArguments returns every meaningful token after the command.
+func (s *Statement) GetArguments() []Node {
+	var arguments []Node
+	for _, t := range s.Tokens {
+		if isIgnored(t) {
+			continue
+		}
+
+		arguments = append(arguments, t)
+	}
+
+	if len(arguments) == 0 {
+		return nil
+	}
+
+	return arguments[1:]
+}
+
 func (s *Statement) NextTokenIs(expected []string, startIndex int) bool {
 	if startIndex >= len(s.Tokens) {
 		return false
@@ -146,12 +180,13 @@ func (s MultiKeyword) String() string {
 
 type RedisToken struct {
 	Node
-	TokenType token.Type
-	From      int
-	To        int
-	LineFrom  int
-	LineTo    int
-	Value     string
+	TokenType   token.Type
+	From        int
+	To          int
+	CurrentLine int
+	LineFrom    int
+	LineTo      int
+	Value       string
 }
 
 func (t RedisToken) Type() token.Type {
@@ -174,18 +209,23 @@ func (t RedisToken) LineEnd() int {
 	return t.LineTo
 }
 
+func (t RedisToken) Line() int {
+	return t.CurrentLine
+}
+
 func (t RedisToken) String() string {
 	return t.Value
 }
 
 func NewToken(token token.Token) RedisToken {
 	return RedisToken{
-		TokenType: token.Type,
-		From:      token.Start,
-		To:        token.End,
-		LineFrom:  token.LineStart,
-		LineTo:    token.LineEnd,
-		Value:     token.Value,
+		TokenType:   token.Type,
+		From:        token.Start,
+		To:          token.End,
+		CurrentLine: token.Line,
+		LineFrom:    token.LineStart,
+		LineTo:      token.LineEnd,
+		Value:       token.Value,
 	}
 }
 
@@ -193,6 +233,23 @@ func New(tokens []RedisToken) []TokenList {
 	return parseMultiKeywords(parseStatements(tokens))
 }
 
+// Parse tokenizes the text and returns its statements.
+func Parse(text string) []TokenList {
+	if text == "" {
+		return nil
+	}
+
+	tokenizer := token.Tokenizer{}
+	tokens := tokenizer.Tokenize(text)
+
+	var redisTokens []RedisToken
+	for _, t := range tokens {
+		redisTokens = append(redisTokens, NewToken(t))
+	}
+
+	return New(redisTokens)
+}
+
 func GetSelectedStatement(tokens []TokenList, line int, position int) (TokenList, int) {
 	for _, s := range tokens {
 		if s.Line() != line {
@@ -210,17 +267,14 @@ func GetSelectedStatement(tokens []TokenList, line int, position int) (TokenList
 func parseStatements(tokens []RedisToken) []TokenList {
 	var result []TokenList
 
-	var line int
-	tokenList := &Statement{CurrentLine: line}
+	tokenList := &Statement{}
 	for i := 0; i < len(tokens); i++ {
 		t := tokens[i]
 		tokenList.AddToken(t)
 		if (t.TokenType == token.Semicolon || t.TokenType == token.Newline) && i+1 < len(tokens) {
 			result = append(result, tokenList)
-			if t.TokenType == token.Newline {
-				line++
-			}
-			tokenList = &Statement{CurrentLine: line}
+			// a statement starts in the line of its first token since strings can have line breaks
+			tokenList = &Statement{CurrentLine: tokens[i+1].CurrentLine}
 		}
 	}
 
@@ -230,16 +284,22 @@ func parseStatements(tokens []RedisToken) []TokenList {
 }
 
 var multiKeywords = map[string][]string{
-	"ACL":     {"LOAD", "SAVE", "LIST", "USERS", "GETUSER", "SETUSER", "DELUSER", "CAT", "GENPASS", "WHOAMI", "LOG", "HELP"},
-	"CLIENT":  {"CACHING", "ID", "INFO", "KILL", "LIST", "GETNAME", "GETREDIR", "UNPAUSE", "PAUSE", "REPLY", "SETNAME", "TRACKING", "TRACKINGINFO", "UNBLOCK"},
-	"CLUSTER": {"ADDSLOTS", "BUMPEPOCH", "COUNT-FAILURE-REPORTS", "COUNTKEYSINSLOT", "DELSLOTS", "FAILOVER", "FLUSHSLOTS", "FORGET", "GETKEYSINSLOT", "INFO", "KEYSLOT", "MEET", "MYID", "NODES", "REPLICATE", "RESET", "SAVECONFIG", "SET-CONFIG-EPOCH", "SETSLOT", "SLAVES", "REPLICAS", "SLOTS"},
-	"COMMAND": {"COUNT", "GETKEYS", "INFO"},
-	"CONFIG":  {"GET", "REWRITE", "SET", "RESETSTAT"},
-	"DEBUG":   {"OBJECT", "SEGFAULT"},
-	"MEMORY":  {"DOCTOR", "HELP", "MALLOC-STATS", "PURGE", "STATS", "USAGE"},
-	"MODULE":  {"LIST", "LOAD", "UNLOAD"},
-	"SCRIPT":  {"DEBUG", "EXISTS", "FLUSH", "KILL", "LOAD"},
-	"LATENCY": {"DOCTOR", "GRAPH", "HISTORY", "LATEST", "RESET", "HELP"},
+	"ACL":      {"CAT", "DELUSER", "DRYRUN", "GENPASS", "GETUSER", "HELP", "LIST", "LOAD", "LOG", "SAVE", "SETUSER", "USERS", "WHOAMI"},
+	"CLIENT":   {"CACHING", "GETNAME", "GETREDIR", "HELP", "ID", "INFO", "KILL", "LIST", "NO-EVICT", "NO-TOUCH", "PAUSE", "REPLY", "SETINFO", "SETNAME", "TRACKING", "TRACKINGINFO", "UNBLOCK", "UNPAUSE"},
+	"CLUSTER":  {"ADDSLOTS", "ADDSLOTSRANGE", "BUMPEPOCH", "COUNT-FAILURE-REPORTS", "COUNTKEYSINSLOT", "DELSLOTS", "DELSLOTSRANGE", "FAILOVER", "FLUSHSLOTS", "FORGET", "GETKEYSINSLOT", "HELP", "INFO", "KEYSLOT", "LINKS", "MEET", "MYID", "MYSHARDID", "NODES", "REPLICAS", "REPLICATE", "RESET", "SAVECONFIG", "SET-CONFIG-EPOCH", "SETSLOT", "SHARDS", "SLAVES", "SLOTS"},
+	"COMMAND":  {"COUNT", "DOCS", "GETKEYS", "GETKEYSANDFLAGS", "HELP", "INFO", "LIST"},
+	"CONFIG":   {"GET", "HELP", "RESETSTAT", "REWRITE", "SET"},
+	"DEBUG":    {"OBJECT", "SEGFAULT"},
+	"FUNCTION": {"DELETE", "DUMP", "FLUSH", "HELP", "KILL", "LIST", "LOAD", "RESTORE", "STATS"},
+	"LATENCY":  {"DOCTOR", "GRAPH", "HELP", "HISTOGRAM", "HISTORY", "LATEST", "RESET"},
+	"MEMORY":   {"DOCTOR", "HELP", "MALLOC-STATS", "PURGE", "STATS", "USAGE"},
+	"MODULE":   {"HELP", "LIST", "LOAD", "LOADEX", "UNLOAD"},
+	"OBJECT":   {"ENCODING", "FREQ", "HELP", "IDLETIME", "REFCOUNT"},
+	"PUBSUB":   {"CHANNELS", "HELP", "NUMPAT", "NUMSUB", "SHARDCHANNELS", "SHARDNUMSUB"},
+	"SCRIPT":   {"DEBUG", "EXISTS", "FLUSH", "HELP", "KILL", "LOAD"},
+	"SLOWLOG":  {"GET", "HELP", "LEN", "RESET"},
+	"XGROUP":   {"CREATE", "CREATECONSUMER", "DELCONSUMER", "DESTROY", "HELP", "SETID"},
+	"XINFO":    {"CONSUMERS", "GROUPS", "HELP", "STREAM"},
 }
 
 func parseMultiKeywords(tokens []TokenList) []TokenList {
@@ -261,24 +321,18 @@ func parseMultiKeywords(tokens []TokenList) []TokenList {
 				continue
 			}
 
-			expectedKeywords, ok := multiKeywords[innerToken.String()]
-			if ok {
-				if len(multiKeyword.Tokens) == 0 {
-					if !t.NextTokenIs(expectedKeywords, i+1) {
-						newTokens = append(newTokens, innerToken)
-					} else {
-						multiKeyword.Tokens = append(multiKeyword.Tokens, innerToken)
-					}
+			// the subcommand can also be a command with subcommands (e.g. OBJECT in DEBUG OBJECT)
+			if len(multiKeyword.Tokens) > 0 {
+				multiKeyword.Tokens = append(multiKeyword.Tokens, innerToken)
+				newTokens = append(newTokens, multiKeyword)
+				multiKeyword = MultiKeyword{CurrentLine: t.Line()}
+				continue
+			}
 
-					continue
-				}
-			} else {
-				if len(multiKeyword.Tokens) > 0 {
-					multiKeyword.Tokens = append(multiKeyword.Tokens, innerToken)
-					newTokens = append(newTokens, multiKeyword)
-					multiKeyword = MultiKeyword{CurrentLine: t.Line()}
-					continue
-				}
+			expectedKeywords, ok := multiKeywords[strings.ToUpper(innerToken.String())]
+			if ok && t.NextTokenIs(expectedKeywords, i+1) {
+				multiKeyword.Tokens = append(multiKeyword.Tokens, innerToken)
+				continue
 			}
 
 			newTokens = append(newTokens, innerToken)
@@ -290,9 +344,47 @@ func parseMultiKeywords(tokens []TokenList) []TokenList {
 	return tokens
 }
 
+// GetSelectedToken returns the statement and the token under the given line and position.
+// The token is nil if there is no token in that position.
+func GetSelectedToken(statements []TokenList, line int, position int) (TokenList, Node) {
+	for _, s := range statements {
+		if s.Line() > line {
+			break
+		}
+
+		for _, t := range s.GetTokens() {
+			if isIgnored(t) {
+				continue
+			}
+
+			if ContainsPosition(t, line, position) {
+				return s, t
+			}
+		}
+	}
+
+	return nil, nil
+}
+
+// CommandName returns the command of the statement normalized as it is in the command list
+// (e.g. "acl  getuser" becomes "ACL GETUSER").
+func CommandName(s TokenList) string {
+	command := s.GetCommand()
+	if command == nil {
+		return ""
+	}
+
+	return strings.ToUpper(strings.Join(strings.Fields(command.String()), " "))
+}
+
+// isIgnored reports whether the node is not part of the command or its arguments.
+func isIgnored(n Node) bool {
+	return n.Type() == token.Space || n.Type() == token.Newline || n.Type() == token.Semicolon || n.Type() == token.Comment
+}
+
 func hasExpectedKeyword(expected []string, actual string) bool {
 	for _, v := range expected {
-		if v == actual {
+		if strings.EqualFold(v, actual) {
 			return true
 		}
 	}
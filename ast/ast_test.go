@@ -2,6 +2,7 @@ package ast
 
 import (
 	"github.com/fagnercarvalho/redis-lsp/token"
+	"reflect"
 	"testing"
 )
 
@@ -144,6 +145,24 @@ func TestParseKeywords(t *testing.T) {
 			1,
 			[]string{"LATENCY LATEST"},
 		},
+		{
+			"Lowercase multikeyword",
+			"client list",
+			1,
+			[]string{"client list"},
+		},
+		{
+			"Container subcommand",
+			"OBJECT ENCODING user:1;XINFO STREAM events FULL",
+			2,
+			[]string{"OBJECT ENCODING", "XINFO STREAM"},
+		},
+		{
+			"Subcommand with subcommands",
+			"DEBUG OBJECT user:1",
+			1,
+			[]string{"DEBUG OBJECT"},
+		},
 		{
 			"No multikeyword",
 			"GET test",
@@ -291,3 +310,196 @@ func TestGetSelectedStatement(t *testing.T) {
 		})
 	}
 }
+
+func TestGetSelectedToken(t *testing.T) {
+	tests := []struct {
+		Name              string
+		Statements        string
+		Line              int
+		Position          int
+		ExpectedToken     string
+		ExpectedCommand   string
+		ExpectedArguments []string
+	}{
+		{
+			"Key argument",
+			"HGETALL user:42",
+			0,
+			10,
+			"user:42",
+			"HGETALL",
+			[]string{"user:42"},
+		},
+		{
+			"Command in second line",
+			"GET test1\nSET test2 value",
+			1,
+			1,
+			"SET",
+			"SET",
+			[]string{"test2", "value"},
+		},
+		{
+			"Multi keyword",
+			"MEMORY USAGE key",
+			0,
+			14,
+			"key",
+			"MEMORY USAGE",
+			[]string{"key"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			statement, selected := GetSelectedToken(Parse(test.Statements), test.Line, test.Position)
+			if selected == nil {
+				t.Fatalf("%v - Expected token %v but got none", test.Name, test.ExpectedToken)
+			}
+
+			if selected.String() != test.ExpectedToken {
+				t.Errorf("%v - Unexpected token: %v (expected %v)", test.Name, selected.String(), test.ExpectedToken)
+			}
+
+			if CommandName(statement) != test.ExpectedCommand {
+				t.Errorf("%v - Unexpected command: %v (expected %v)", test.Name, CommandName(statement), test.ExpectedCommand)
+			}
+
+			arguments := statement.GetArguments()
+			if len(arguments) != len(test.ExpectedArguments) {
+				t.Fatalf("%v - Unexpected amount of arguments: %v (expected %v)", test.Name, len(arguments), len(test.ExpectedArguments))
+			}
+
+			for i, a := range arguments {
+				if a.String() != test.ExpectedArguments[i] {
+					t.Errorf("%v - Unexpected argument: %v (expected %v)", test.Name, a.String(), test.ExpectedArguments[i])
+				}
+			}
+		})
+	}
+}
+
+func TestGetRegions(t *testing.T) {
+	tests := []struct {
+		Name            string
+		Statements      string
+		ExpectedRegions []Region
+	}{
+		{
+			"Single region",
+			"# region seed data\nSET a 1\nSET b 2\n# endregion\nGET a",
+			[]Region{{Name: "seed data", StartLine: 0, EndLine: 3}},
+		},
+		{
+			"Nested regions",
+			"// region outer\n// region inner\nGET a\n// endregion\n// endregion",
+			[]Region{{Name: "inner", StartLine: 1, EndLine: 3}, {Name: "outer", StartLine: 0, EndLine: 4}},
+		},
+		{
+			"Region without end",
+			"GET a\n# region tail\nGET b\nGET c",
+			[]Region{{Name: "tail", StartLine: 1, EndLine: 3}},
+		},
+		{
+			"Comment after command is not a region",
+			"GET a # region nope",
+			nil,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			regions := GetRegions(Parse(test.Statements))
+			if len(regions) != len(test.ExpectedRegions) {
+				t.Fatalf("%v - Unexpected amount of regions: %v (expected %v)", test.Name, len(regions), len(test.ExpectedRegions))
+			}
+
+			for i, r := range regions {
+				if r != test.ExpectedRegions[i] {
+					t.Errorf("%v - Unexpected region: %+v (expected %+v)", test.Name, r, test.ExpectedRegions[i])
+				}
+			}
+		})
+	}
+}
+
+func TestGetTransactions(t *testing.T) {
+	tests := []struct {
+		Name                 string
+		Statements           string
+		ExpectedTransactions []Region
+	}{
+		{
+			"Executed transaction",
+			"GET a\nmulti\nINCR a\nINCR b\nexec",
+			[]Region{{Name: "MULTI", StartLine: 1, EndLine: 4}},
+		},
+		{
+			"Discarded transaction in one line",
+			"MULTI; INCR a; DISCARD",
+			[]Region{{Name: "MULTI", StartLine: 0, EndLine: 0}},
+		},
+		{
+			"Transaction without end",
+			"MULTI\nINCR a\nINCR b",
+			[]Region{{Name: "MULTI", StartLine: 0, EndLine: 2}},
+		},
+		{
+			"Exec without multi",
+			"INCR a\nEXEC",
+			nil,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			transactions := GetTransactions(Parse(test.Statements))
+			if len(transactions) != len(test.ExpectedTransactions) {
+				t.Fatalf("%v - Unexpected amount of transactions: %v (expected %v)", test.Name, len(transactions), len(test.ExpectedTransactions))
+			}
+
+			for i, r := range transactions {
+				if r != test.ExpectedTransactions[i] {
+					t.Errorf("%v - Unexpected transaction: %+v (expected %+v)", test.Name, r, test.ExpectedTransactions[i])
+				}
+			}
+		})
+	}
+}
+
+func TestGetScriptArguments(t *testing.T) {
+	tests := []struct {
+		Text              string
+		ExpectedKeys      []string
+		ExpectedArguments []string
+		ExpectedOk        bool
+	}{
+		{"EVAL \"return 1\" 2 a b c", []string{"a", "b"}, []string{"c"}, true},
+		{"EVALSHA abc \"1\" a", []string{"a"}, nil, true},
+		{"EVAL_RO \"return 1\" 0 a", nil, []string{"a"}, true},
+		{"EVAL \"return 1\" 3 a", []string{"a"}, nil, true},
+		{"EVAL \"return 1\" x a", nil, nil, false},
+		{"EVAL \"return 1\"", nil, nil, false},
+		{"GET a", nil, nil, false},
+	}
+
+	for _, test := range tests {
+		keys, arguments, ok := GetScriptArguments(Parse(test.Text)[0])
+		if ok != test.ExpectedOk {
+			t.Errorf("%v - Unexpected ok: %v", test.Text, ok)
+		}
+
+		var actualKeys, actualArguments []string
+		for _, k := range keys {
+			actualKeys = append(actualKeys, k.String())
+		}
+
+		for _, a := range arguments {
+			actualArguments = append(actualArguments, a.String())
+		}
+
+		if !reflect.DeepEqual(actualKeys, test.ExpectedKeys) || !reflect.DeepEqual(actualArguments, test.ExpectedArguments) {
+			t.Errorf("%v - Unexpected keys %v and arguments %v (expected %v and %v)", test.Text, actualKeys, actualArguments, test.ExpectedKeys, test.ExpectedArguments)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package ast
+
+import (
+	"strings"
+
+	"github.com/fagnercarvalho/redis-lsp/token"
+)
+
+// Region is a block of lines between a "# region name" and a "# endregion" comment.
+type Region struct {
+	Name      string
+	StartLine int
+	EndLine   int
+}
+
+// CommentText returns the text of a comment without the # or // prefix.
+func CommentText(comment string) string {
+	if strings.HasPrefix(comment, "//") {
+		return strings.TrimSpace(comment[2:])
+	}
+
+	return strings.TrimSpace(strings.TrimPrefix(comment, "#"))
+}
+
+// GetComment returns the comment of the statement or nil if it has none.
+func GetComment(s TokenList) Node {
+	for _, t := range s.GetTokens() {
+		if t.Type() == token.Comment {
+			return t
+		}
+	}
+
+	return nil
+}
+
+// GetRegions returns the regions defined by comments, regions can be nested
+// and a region without an end goes until the last statement.
+func GetRegions(statements []TokenList) []Region {
+	var regions []Region
+	var open []Region
+	lastLine := 0
+	for _, s := range statements {
+		lastLine = s.Line()
+
+		// only comments in their own line define regions
+		comment := GetComment(s)
+		if comment == nil || s.GetCommand() != nil {
+			continue
+		}
+
+		marker, name := regionMarker(comment.String())
+		switch marker {
+		case "region":
+			open = append(open, Region{Name: name, StartLine: s.Line()})
+		case "endregion":
+			if len(open) == 0 {
+				continue
+			}
+
+			region := open[len(open)-1]
+			region.EndLine = s.Line()
+			open = open[:len(open)-1]
+			regions = append(regions, region)
+		}
+	}
+
+	for i := len(open) - 1; i >= 0; i-- {
+		region := open[i]
+		region.EndLine = lastLine
+		regions = append(regions, region)
+	}
+
+	return regions
+}
+
+// IsRegionMarker reports whether the comment starts or ends a region.
+func IsRegionMarker(comment string) bool {
+	marker, _ := regionMarker(comment)
+	return marker != ""
+}
+
+// regionMarker returns "region" and the name of the region for a "# region name" comment,
+// "endregion" for a "# endregion" comment and an empty string for other comments.
+func regionMarker(comment string) (string, string) {
+	text := CommentText(comment)
+	fields := strings.Fields(text)
+	if len(fields) == 0 {
+		return "", ""
+	}
+
+	switch strings.ToLower(fields[0]) {
+	case "region", "#region":
+		return "region", strings.TrimSpace(text[len(fields[0]):])
+	case "endregion", "#endregion":
+		return "endregion", ""
+	}
+
+	return "", ""
+}
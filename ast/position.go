@@ -0,0 +1,57 @@
+package ast
+
+import "strings"
+
+// EndLine returns the line where the token ends, which is not the line where it starts for strings with line breaks.
+func EndLine(n Node) int {
+	return n.Line() + strings.Count(n.String(), "\n")
+}
+
+// OffsetPosition returns the line and the character in the line of an offset in the value of the token.
+func OffsetPosition(n Node, offset int) (int, int) {
+	before := n.String()[:offset]
+	newline := strings.LastIndexByte(before, '\n')
+	if newline == -1 {
+		return n.Line(), n.LineStart() + offset
+	}
+
+	return n.Line() + strings.Count(before, "\n"), offset - newline - 1
+}
+
+// ContainsPosition reports whether the position is inside the token or right after it.
+func ContainsPosition(n Node, line int, position int) bool {
+	if line < n.Line() || line > EndLine(n) {
+		return false
+	}
+
+	if line == n.Line() && position < n.LineStart() {
+		return false
+	}
+
+	if line == EndLine(n) && position > n.LineEnd()+1 {
+		return false
+	}
+
+	return true
+}
+
+// PositionOffset returns the offset in the value of the token of a line and a character in the line,
+// it is the opposite of OffsetPosition.
+func PositionOffset(n Node, line int, position int) int {
+	if line == n.Line() {
+		return position - n.LineStart()
+	}
+
+	value := n.String()
+	offset := 0
+	for i := n.Line(); i < line; i++ {
+		newline := strings.IndexByte(value[offset:], '\n')
+		if newline == -1 {
+			return len(value)
+		}
+
+		offset += newline + 1
+	}
+
+	return offset + position
+}
@@ -0,0 +1,85 @@
+package ast
+
+import (
+	"strconv"
+
+	"github.com/fagnercarvalho/redis-lsp/token"
+)
+
+// scriptCommands are the commands whose first argument is a Lua script.
+var scriptCommands = map[string]bool{
+	"EVAL":        true,
+	"EVAL_RO":     true,
+	"SCRIPT LOAD": true,
+}
+
+// GetScript returns the argument with the Lua script of an EVAL, EVAL_RO or SCRIPT LOAD statement or nil if there is none.
+func GetScript(s TokenList) Node {
+	if !scriptCommands[CommandName(s)] {
+		return nil
+	}
+
+	arguments := s.GetArguments()
+	if len(arguments) == 0 {
+		return nil
+	}
+
+	return arguments[0]
+}
+
+// numkeysCommands are the commands that run a script with numkeys followed by the keys and the arguments.
+var numkeysCommands = map[string]bool{
+	"EVAL":       true,
+	"EVAL_RO":    true,
+	"EVALSHA":    true,
+	"EVALSHA_RO": true,
+	"FCALL":      true,
+	"FCALL_RO":   true,
+}
+
+// HasNumkeys reports whether the statement runs a script with numkeys followed by the keys and the arguments
+// (e.g. EVAL script numkeys key... arg...).
+func HasNumkeys(s TokenList) bool {
+	return numkeysCommands[CommandName(s)]
+}
+
+// GetScriptArguments returns the keys and the arguments passed to the script of an EVAL or EVALSHA statement
+// (e.g. "a" and "b" in EVAL script 1 a b), ok is false when there is no valid numkeys argument.
+// When numkeys is greater than the number of arguments every argument is returned as a key.
+func GetScriptArguments(s TokenList) (keys []Node, arguments []Node, ok bool) {
+	if !HasNumkeys(s) {
+		return nil, nil, false
+	}
+
+	values := s.GetArguments()
+	if len(values) < 2 {
+		return nil, nil, false
+	}
+
+	numkeys, ok := Numkeys(values[1])
+	if !ok {
+		return nil, nil, false
+	}
+
+	values = values[2:]
+	if numkeys > len(values) {
+		numkeys = len(values)
+	}
+
+	return values[:numkeys], values[numkeys:], true
+}
+
+// Numkeys returns the value of a numkeys argument, ok is false when it is not a non-negative integer.
+func Numkeys(n Node) (int, bool) {
+	value, err := token.Unquote(n.String())
+	if err != nil {
+		return 0, false
+	}
+
+	numkeys, err := strconv.Atoi(value)
+	if err != nil || numkeys < 0 {
+		return 0, false
+	}
+
+	return numkeys, true
+}
@@ -0,0 +1,35 @@
+package ast
+
+// GetTransactions returns the MULTI blocks, from the MULTI to the EXEC or DISCARD statement.
+// A transaction without an end goes until the last statement.
+func GetTransactions(statements []TokenList) []Region {
+	var transactions []Region
+	var open *Region
+	lastLine := 0
+	for _, s := range statements {
+		lastLine = s.Line()
+
+		switch CommandName(s) {
+		case "MULTI":
+			// Redis does not allow nested transactions, so the block starts at the first MULTI
+			if open == nil {
+				open = &Region{Name: "MULTI", StartLine: s.Line()}
+			}
+		case "EXEC", "DISCARD":
+			if open == nil {
+				continue
+			}
+
+			open.EndLine = s.Line()
+			transactions = append(transactions, *open)
+			open = nil
+		}
+	}
+
+	if open != nil {
+		open.EndLine = lastLine
+		transactions = append(transactions, *open)
+	}
+
+	return transactions
+}
@@ -2,6 +2,11 @@ package client
 
 import (
 	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
 	"github.com/go-redis/redis/v8"
 )
 
@@ -10,8 +15,23 @@ type Redis struct {
 	Keys []string
 
 	client *redis.Client
+
+	// version is the version of the server, it is only fetched once since it does not change while connected
+	version *serverVersion
 }
 
+// serverVersion is the version of the server once it is fetched, a failure is kept for versionRetry
+// so an unreachable server does not make every caller wait for INFO to time out.
+type serverVersion struct {
+	mu       sync.Mutex
+	value    string
+	err      error
+	failedAt time.Time
+}
+
+// versionRetry is how long a failure to get the version is returned before INFO is sent again.
+const versionRetry = 30 * time.Second
+
 func New(address string, username string, password string, db int, dbCache bool) (Redis, error) {
 	client := redis.NewClient(&redis.Options{
 		Addr: address,
@@ -20,7 +40,7 @@ func New(address string, username string, password string, db int, dbCache bool)
 		DB: db,
 	})
 
-	c := Redis{client:client}
+	c := Redis{client:client, version: &serverVersion{}}
 
 	// TODO: run MONITOR command to stream SET/DEL/ACL SETUSER/ACL DELUSER commands to update users/keys lists
 
@@ -82,4 +102,146 @@ func (r Redis) getKeys() ([]string, error) {
 	}
 
 	return result, nil
-}
\ No newline at end of file
+}
+
+// GetHashFields returns the first fields of a hash.
+func (r Redis) GetHashFields(ctx context.Context, key string) ([]string, error) {
+	val, _, err := r.client.HScan(ctx, key, 0, "", 50).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	var fields []string
+	for i := 0; i < len(val); i += 2 {
+		fields = append(fields, val[i])
+	}
+
+	return fields, nil
+}
+
+func (r Redis) Close() error {
+	return r.client.Close()
+}
+
+// ScanKeys returns every key matching the pattern.
+func (r Redis) ScanKeys(ctx context.Context, pattern string) ([]string, error) {
+	var keys []string
+	var cursor uint64
+	for {
+		page, next, err := r.client.Scan(ctx, cursor, pattern, 100).Result()
+		if err != nil {
+			return keys, err
+		}
+
+		keys = append(keys, page...)
+		cursor = next
+		if cursor == 0 {
+			return keys, nil
+		}
+	}
+}
+
+// Exists reports whether the key exists.
+func (r Redis) Exists(ctx context.Context, key string) (bool, error) {
+	n, err := r.client.Exists(ctx, key).Result()
+	return n > 0, err
+}
+
+// Rename renames the key, failing if the new key already exists.
+func (r Redis) Rename(ctx context.Context, key string, newKey string) error {
+	renamed, err := r.client.RenameNX(ctx, key, newKey).Result()
+	if err != nil {
+		return err
+	}
+
+	if !renamed {
+		return fmt.Errorf("key %v already exists", newKey)
+	}
+
+	return nil
+}
+
+// GetFunctions returns the functions loaded with FUNCTION LOAD with the library of each one.
+func (r Redis) GetFunctions(ctx context.Context) (map[string]string, error) {
+	val, err := r.client.Do(ctx, "FUNCTION", "LIST").Result()
+	if err != nil {
+		return nil, err
+	}
+
+	// Each library is a list of names followed by their values.
+	// Example: [[library_name mylib engine LUA functions [[name myfunc description <nil> flags []]]]]
+	functions := map[string]string{}
+	libraries, _ := val.([]interface{})
+	for _, l := range libraries {
+		library := fieldValues(l)
+		name, _ := library["library_name"].(string)
+
+		list, _ := library["functions"].([]interface{})
+		for _, f := range list {
+			if function, ok := fieldValues(f)["name"].(string); ok {
+				functions[function] = name
+			}
+		}
+	}
+
+	return functions, nil
+}
+
+// fieldValues returns the values of a reply with names followed by their values.
+func fieldValues(reply interface{}) map[string]interface{} {
+	values := map[string]interface{}{}
+	list, _ := reply.([]interface{})
+	for i := 0; i+1 < len(list); i += 2 {
+		if name, ok := list[i].(string); ok {
+			values[name] = list[i+1]
+		}
+	}
+
+	return values
+}
+
+// Version returns the version of the server from the redis_version field of INFO server (e.g. "7.2.4").
+// A failure is returned again without sending INFO until versionRetry passes.
+func (r Redis) Version(ctx context.Context) (string, error) {
+	if r.version == nil {
+		return r.fetchVersion(ctx)
+	}
+
+	r.version.mu.Lock()
+	defer r.version.mu.Unlock()
+
+	if r.version.value != "" {
+		return r.version.value, nil
+	}
+
+	if r.version.err != nil && time.Since(r.version.failedAt) < versionRetry {
+		return "", r.version.err
+	}
+
+	version, err := r.fetchVersion(ctx)
+	if err != nil {
+		r.version.err = err
+		r.version.failedAt = time.Now()
+		return "", err
+	}
+
+	r.version.value = version
+
+	return version, nil
+}
+
+// fetchVersion sends INFO server to get the version of the server.
+func (r Redis) fetchVersion(ctx context.Context) (string, error) {
+	info, err := r.client.Info(ctx, "server").Result()
+	if err != nil {
+		return "", err
+	}
+
+	for _, line := range strings.Split(info, "\n") {
+		if strings.HasPrefix(line, "redis_version:") {
+			return strings.TrimSpace(strings.TrimPrefix(line, "redis_version:")), nil
+		}
+	}
+
+	return "", fmt.Errorf("INFO server does not have the redis_version field")
+}
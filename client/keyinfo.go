@@ -0,0 +1,172 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Amount of elements and characters shown in the preview of a key value.
+const (
+	previewElements = 10
+	previewLength   = 200
+)
+
+type KeyInfo struct {
+	Exists bool
+	Type   string
+	TTL    time.Duration
+	PTTL   time.Duration
+	// Memory and Encoding are empty when the server refuses MEMORY USAGE or OBJECT ENCODING (e.g. by an ACL rule)
+	Memory   int64
+	Encoding string
+	Length   int64
+	Preview  string
+}
+
+// GetKeyInfo returns the metadata and a truncated preview of the value of a key.
+// The given context should have a short timeout since this is called while the user is hovering a key.
+func (r Redis) GetKeyInfo(ctx context.Context, key string) (KeyInfo, error) {
+	keyType, err := r.client.Type(ctx, key).Result()
+	if err != nil {
+		return KeyInfo{}, err
+	}
+
+	if keyType == "none" {
+		return KeyInfo{}, nil
+	}
+
+	info := KeyInfo{Exists: true, Type: keyType}
+
+	info.TTL, err = r.client.TTL(ctx, key).Result()
+	if err != nil {
+		return info, err
+	}
+
+	info.PTTL, err = r.client.PTTL(ctx, key).Result()
+	if err != nil {
+		return info, err
+	}
+
+	// these are only informative so the rest of the information is still returned without them
+	info.Memory, err = r.client.MemoryUsage(ctx, key).Result()
+	if err != nil {
+		info.Memory = 0
+	}
+
+	info.Encoding, err = r.client.ObjectEncoding(ctx, key).Result()
+	if err != nil {
+		info.Encoding = ""
+	}
+
+	info.Length, err = r.getLength(ctx, keyType, key)
+	if err != nil {
+		return info, err
+	}
+
+	info.Preview, err = r.getPreview(ctx, keyType, key)
+	if err != nil {
+		return info, err
+	}
+
+	return info, nil
+}
+
+// GetCardinality returns the number of elements of a collection, it is 0 for strings and keys that do not exist.
+func (r Redis) GetCardinality(ctx context.Context, key string) (int64, error) {
+	keyType, err := r.client.Type(ctx, key).Result()
+	if err != nil || keyType == "string" {
+		return 0, err
+	}
+
+	return r.getLength(ctx, keyType, key)
+}
+
+func (r Redis) getLength(ctx context.Context, keyType string, key string) (int64, error) {
+	switch keyType {
+	case "string":
+		return r.client.StrLen(ctx, key).Result()
+	case "list":
+		return r.client.LLen(ctx, key).Result()
+	case "hash":
+		return r.client.HLen(ctx, key).Result()
+	case "set":
+		return r.client.SCard(ctx, key).Result()
+	case "zset":
+		return r.client.ZCard(ctx, key).Result()
+	case "stream":
+		return r.client.XLen(ctx, key).Result()
+	}
+
+	return 0, nil
+}
+
+func (r Redis) getPreview(ctx context.Context, keyType string, key string) (string, error) {
+	var preview string
+	switch keyType {
+	case "string":
+		val, err := r.client.GetRange(ctx, key, 0, previewLength).Result()
+		if err != nil {
+			return "", err
+		}
+
+		preview = val
+	case "list":
+		val, err := r.client.LRange(ctx, key, 0, previewElements-1).Result()
+		if err != nil {
+			return "", err
+		}
+
+		preview = strings.Join(val, "\n")
+	case "hash":
+		val, _, err := r.client.HScan(ctx, key, 0, "", previewElements).Result()
+		if err != nil {
+			return "", err
+		}
+
+		var fields []string
+		for i := 0; i+1 < len(val); i += 2 {
+			fields = append(fields, fmt.Sprintf("%v: %v", val[i], val[i+1]))
+		}
+
+		preview = strings.Join(fields, "\n")
+	case "set":
+		val, _, err := r.client.SScan(ctx, key, 0, "", previewElements).Result()
+		if err != nil {
+			return "", err
+		}
+
+		preview = strings.Join(val, "\n")
+	case "zset":
+		val, err := r.client.ZRangeWithScores(ctx, key, 0, previewElements-1).Result()
+		if err != nil {
+			return "", err
+		}
+
+		var members []string
+		for _, z := range val {
+			members = append(members, fmt.Sprintf("%v: %v", z.Member, z.Score))
+		}
+
+		preview = strings.Join(members, "\n")
+	case "stream":
+		val, err := r.client.XRangeN(ctx, key, "-", "+", previewElements).Result()
+		if err != nil {
+			return "", err
+		}
+
+		var entries []string
+		for _, m := range val {
+			entries = append(entries, fmt.Sprintf("%v: %v", m.ID, m.Values))
+		}
+
+		preview = strings.Join(entries, "\n")
+	}
+
+	if len(preview) > previewLength {
+		preview = preview[:previewLength] + "..."
+	}
+
+	return preview, nil
+}
@@ -0,0 +1,113 @@
+package client
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Profile is a named Redis connection (e.g. local, staging, prod).
+type Profile struct {
+	Name     string `json:"name"`
+	Address  string `json:"address"`
+	Username string `json:"username"`
+	Password string `json:"password"`
+	Database int    `json:"database"`
+}
+
+// Manager holds the connection profiles and connects to each one of them only when it is first used.
+type Manager struct {
+	profiles map[string]Profile
+	clients  map[string]Redis
+	active   string
+	dbCache  bool
+}
+
+func NewManager(profiles []Profile, active string, dbCache bool) *Manager {
+	m := &Manager{profiles: map[string]Profile{}, clients: map[string]Redis{}, active: active, dbCache: dbCache}
+	m.SetProfiles(profiles)
+
+	return m
+}
+
+// SetProfiles replaces the profiles, connections of profiles that changed or were removed are closed.
+func (m *Manager) SetProfiles(profiles []Profile) {
+	updated := map[string]Profile{}
+	for _, p := range profiles {
+		updated[p.Name] = p
+	}
+
+	for name, c := range m.clients {
+		if p, ok := updated[name]; !ok || p != m.profiles[name] {
+			c.Close()
+			delete(m.clients, name)
+		}
+	}
+
+	m.profiles = updated
+}
+
+// Switch changes the profile used by documents that do not choose one.
+func (m *Manager) Switch(name string) error {
+	if _, ok := m.profiles[name]; !ok {
+		return fmt.Errorf("connection %v is not defined", name)
+	}
+
+	m.active = name
+
+	return nil
+}
+
+// Active returns the profile used by documents that do not choose one.
+func (m *Manager) Active() Profile {
+	return m.profiles[m.active]
+}
+
+func (m *Manager) Profile(name string) (Profile, bool) {
+	p, ok := m.profiles[name]
+	return p, ok
+}
+
+// Profiles returns every profile sorted by name.
+func (m *Manager) Profiles() []Profile {
+	var profiles []Profile
+	for _, p := range m.profiles {
+		profiles = append(profiles, p)
+	}
+
+	sort.Slice(profiles, func(i, j int) bool {
+		return profiles[i].Name < profiles[j].Name
+	})
+
+	return profiles
+}
+
+// CacheEnabled reports whether keys, users and fields should be fetched for autocompletion.
+func (m *Manager) CacheEnabled() bool {
+	return m.dbCache
+}
+
+// Client returns the connection for the profile, an empty name means the active profile.
+func (m *Manager) Client(name string) (Redis, error) {
+	if name == "" {
+		name = m.active
+	}
+
+	if c, ok := m.clients[name]; ok {
+		return c, nil
+	}
+
+	p, ok := m.profiles[name]
+	if !ok {
+		return Redis{}, fmt.Errorf("connection %v is not defined", name)
+	}
+
+	c, err := New(p.Address, p.Username, p.Password, p.Database, m.dbCache)
+	if err != nil {
+		c.Close()
+		return c, err
+	}
+
+	m.clients[name] = c
+
+	return c, nil
+}
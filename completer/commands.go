@@ -4,305 +4,493 @@ import (
 	"strings"
 )
 
-var commands = []string{
-	"ACL LOAD",
-	"ACL SAVE",
-	"ACL LIST",
-	"ACL USERS",
-	"ACL GETUSER",
-	"ACL SETUSER",
-	"ACL DELUSER",
-	"ACL CAT",
-	"ACL GENPASS",
-	"ACL WHOAMI",
-	"ACL LOG",
-	"ACL HELP",
-	"APPEND",
-	"AUTH",
-	"BGREWRITEAOF",
-	"BGSAVE",
-	"BITCOUNT",
-	"BITFIELD",
-	"BITOP",
-	"BITPOS",
-	"BLPOP",
-	"BRPOP",
-	"BRPOPLPUSH",
-	"BLMOVE",
-	"BZPOPMIN",
-	"BZPOPMAX",
-	"CLIENT CACHING",
-	"CLIENT ID",
-	"CLIENT INFO",
-	"CLIENT KILL",
-	"CLIENT LIST",
-	"CLIENT GETNAME",
-	"CLIENT GETREDIR",
-	"CLIENT UNPAUSE",
-	"CLIENT PAUSE",
-	"CLIENT REPLY",
-	"CLIENT SETNAME",
-	"CLIENT TRACKING",
-	"CLIENT TRACKINGINFO",
-	"CLIENT UNBLOCK",
-	"CLUSTER ADDSLOTS",
-	"CLUSTER BUMPEPOCH",
-	"CLUSTER COUNT-FAILURE-REPORTS",
-	"CLUSTER COUNTKEYSINSLOT",
-	"CLUSTER DELSLOTS",
-	"CLUSTER FAILOVER",
-	"CLUSTER FLUSHSLOTS",
-	"CLUSTER FORGET",
-	"CLUSTER GETKEYSINSLOT",
-	"CLUSTER INFO",
-	"CLUSTER KEYSLOT",
-	"CLUSTER MEET",
-	"CLUSTER MYID",
-	"CLUSTER NODES",
-	"CLUSTER REPLICATE",
-	"CLUSTER RESET",
-	"CLUSTER SAVECONFIG",
-	"CLUSTER SET-CONFIG-EPOCH",
-	"CLUSTER SETSLOT",
-	"CLUSTER SLAVES",
-	"CLUSTER REPLICAS",
-	"CLUSTER SLOTS",
-	"COMMAND",
-	"COMMAND COUNT",
-	"COMMAND GETKEYS",
-	"COMMAND INFO",
-	"CONFIG GET",
-	"CONFIG REWRITE",
-	"CONFIG SET",
-	"CONFIG RESETSTAT",
-	"COPY",
-	"DBSIZE",
-	"DEBUG OBJECT",
-	"DEBUG SEGFAULT",
-	"DECR",
-	"DECRBY",
-	"DEL",
-	"DISCARD",
-	"DUMP",
-	"ECHO",
-	"EVAL",
-	"EVAL_RO",
-	"EVALSHA",
-	"EVALSHA_RO",
-	"EXEC",
-	"EXISTS",
-	"EXPIRE",
-	"EXPIREAT",
-	"EXPIRETIME",
-	"FAILOVER",
-	"FLUSHALL",
-	"FLUSHDB",
-	"GEOADD",
-	"GEOHASH",
-	"GEOPOS",
-	"GEODIST",
-	"GEORADIUS",
-	"GEORADIUSBYMEMBER",
-	"GEOSEARCH",
-	"GEOSEARCHSTORE",
-	"GET",
-	"GETBIT",
-	"GETDEL",
-	"GETEX",
-	"GETRANGE",
-	"GETSET",
-	"HDEL",
-	"HELLO",
-	"HEXISTS",
-	"HGET",
-	"HGETALL",
-	"HINCRBY",
-	"HINCRBYFLOAT",
-	"HKEYS",
-	"HLEN",
-	"HMGET",
-	"HMSET",
-	"HSET",
-	"HSETNX",
-	"HRANDFIELD",
-	"HSTRLEN",
-	"HVALS",
-	"INCR",
-	"INCRBY",
-	"INCRBYFLOAT",
-	"INFO",
-	"LOLWUT",
-	"KEYS",
-	"LASTSAVE",
-	"LINDEX",
-	"LINSERT",
-	"LLEN",
-	"LPOP",
-	"LPOS",
-	"LPUSH",
-	"LPUSHX",
-	"LRANGE",
-	"LREM",
-	"LSET",
-	"LTRIM",
-	"MEMORY DOCTOR",
-	"MEMORY HELP",
-	"MEMORY MALLOC-STATS",
-	"MEMORY PURGE",
-	"MEMORY STATS",
-	"MEMORY USAGE",
-	"MGET",
-	"MIGRATE",
-	"MODULE LIST",
-	"MODULE LOAD",
-	"MODULE UNLOAD",
-	"MONITOR",
-	"MOVE",
-	"MSET",
-	"MSETNX",
-	"MULTI",
-	"OBJECT",
-	"PERSIST",
-	"PEXPIRE",
-	"PEXPIREAT",
-	"PEXPIRETIME",
-	"PFADD",
-	"PFCOUNT",
-	"PFMERGE",
-	"PING",
-	"PSETEX",
-	"PSUBSCRIBE",
-	"PUBSUB",
-	"PTTL",
-	"PUBLISH",
-	"PUNSUBSCRIBE",
-	"QUIT",
-	"RANDOMKEY",
-	"READONLY",
-	"READWRITE",
-	"RENAME",
-	"RENAMENX",
-	"RESET",
-	"RESTORE",
-	"ROLE",
-	"RPOP",
-	"RPOPLPUSH",
-	"LMOVE",
-	"RPUSH",
-	"RPUSHX",
-	"SADD",
-	"SAVE",
-	"SCARD",
-	"SCRIPT DEBUG",
-	"SCRIPT EXISTS",
-	"SCRIPT FLUSH",
-	"SCRIPT KILL",
-	"SCRIPT LOAD",
-	"SDIFF",
-	"SDIFFSTORE",
-	"SELECT",
-	"SET",
-	"SETBIT",
-	"SETEX",
-	"SETNX",
-	"SETRANGE",
-	"SHUTDOWN",
-	"SINTER",
-	"SINTERSTORE",
-	"SISMEMBER",
-	"SMISMEMBER",
-	"SLAVEOF",
-	"REPLICAOF",
-	"SLOWLOG",
-	"SMEMBERS",
-	"SMOVE",
-	"SORT",
-	"SPOP",
-	"SRANDMEMBER",
-	"SREM",
-	"STRALGO",
-	"STRLEN",
-	"SUBSCRIBE",
-	"SUNION",
-	"SUNIONSTORE",
-	"SWAPDB",
-	"SYNC",
-	"PSYNC",
-	"TIME",
-	"TOUCH",
-	"TTL",
-	"TYPE",
-	"UNSUBSCRIBE",
-	"UNLINK",
-	"UNWATCH",
-	"WAIT",
-	"WATCH",
-	"ZADD",
-	"ZCARD",
-	"ZCOUNT",
-	"ZDIFF",
-	"ZDIFFSTORE",
-	"ZINCRBY",
-	"ZINTER",
-	"ZINTERSTORE",
-	"ZLEXCOUNT",
-	"ZPOPMAX",
-	"ZPOPMIN",
-	"ZRANDMEMBER",
-	"ZRANGESTORE",
-	"ZRANGE",
-	"ZRANGEBYLEX",
-	"ZREVRANGEBYLEX",
-	"ZRANGEBYSCORE",
-	"ZRANK",
-	"ZREM",
-	"ZREMRANGEBYLEX",
-	"ZREMRANGEBYRANK",
-	"ZREMRANGEBYSCORE",
-	"ZREVRANGE",
-	"ZREVRANGEBYSCORE",
-	"ZREVRANK",
-	"ZSCORE",
-	"ZUNION",
-	"ZMSCORE",
-	"ZUNIONSTORE",
-	"SCAN",
-	"SSCAN",
-	"HSCAN",
-	"ZSCAN",
-	"XINFO",
-	"XADD",
-	"XTRIM",
-	"XDELID",
-	"XRANGE",
-	"XREVRANGE",
-	"XLEN",
-	"XREAD",
-	"XGROUP",
-	"XREADGROUP",
-	"XACK",
-	"XCLAIM",
-	"XAUTOCLAIM",
-	"XPENDING",
-	"LATENCY DOCTOR",
-	"LATENCY GRAPH",
-	"LATENCY HISTORY",
-	"LATENCY LATEST",
-	"LATENCY RESET",
-	"LATENCY HELP",
+// Command is an entry of the command catalogue.
+// Arguments uses the same syntax as the redis-cli help:
+// lowercase words are values, uppercase words are literal tokens, [] wraps optional arguments,
+// () wraps required groups, | separates alternatives and ... means the group can be repeated.
+type Command struct {
+	Name      string
+	Arguments string
+	Since     string
+}
+
+var commands = []Command{
+	{"ACL CAT", "[categoryname]", "6.0.0"},
+	{"ACL DELUSER", "username [username ...]", "6.0.0"},
+	{"ACL DRYRUN", "username command [arg [arg ...]]", "7.0.0"},
+	{"ACL GENPASS", "[bits]", "6.0.0"},
+	{"ACL GETUSER", "username", "6.0.0"},
+	{"ACL HELP", "", "6.0.0"},
+	{"ACL LIST", "", "6.0.0"},
+	{"ACL LOAD", "", "6.0.0"},
+	{"ACL LOG", "[count | RESET]", "6.0.0"},
+	{"ACL SAVE", "", "6.0.0"},
+	{"ACL SETUSER", "username [rule [rule ...]]", "6.0.0"},
+	{"ACL USERS", "", "6.0.0"},
+	{"ACL WHOAMI", "", "6.0.0"},
+	{"APPEND", "key value", "2.0.0"},
+	{"ASKING", "", "3.0.0"},
+	{"AUTH", "[username] password", "1.0.0"},
+	{"BGREWRITEAOF", "", "1.0.0"},
+	{"BGSAVE", "[SCHEDULE]", "1.0.0"},
+	{"BITCOUNT", "key [start end [BYTE | BIT]]", "2.6.0"},
+	{"BITFIELD", "key [OVERFLOW (WRAP | SAT | FAIL) | GET encoding offset | SET encoding offset value | INCRBY encoding offset increment ...]", "3.2.0"},
+	{"BITFIELD_RO", "key [GET encoding offset [GET encoding offset ...]]", "6.0.0"},
+	{"BITOP", "operation destkey key [key ...]", "2.6.0"},
+	{"BITPOS", "key bit [start [end [BYTE | BIT]]]", "2.8.7"},
+	{"BLMOVE", "source destination (LEFT | RIGHT) (LEFT | RIGHT) timeout", "6.2.0"},
+	{"BLMPOP", "timeout numkeys key [key ...] (LEFT | RIGHT) [COUNT count]", "7.0.0"},
+	{"BLPOP", "key [key ...] timeout", "2.0.0"},
+	{"BRPOP", "key [key ...] timeout", "2.0.0"},
+	{"BRPOPLPUSH", "source destination timeout", "2.2.0"},
+	{"BZMPOP", "timeout numkeys key [key ...] (MIN | MAX) [COUNT count]", "7.0.0"},
+	{"BZPOPMAX", "key [key ...] timeout", "5.0.0"},
+	{"BZPOPMIN", "key [key ...] timeout", "5.0.0"},
+	{"CLIENT CACHING", "(YES | NO)", "6.0.0"},
+	{"CLIENT GETNAME", "", "2.6.9"},
+	{"CLIENT GETREDIR", "", "6.0.0"},
+	{"CLIENT HELP", "", "5.0.0"},
+	{"CLIENT ID", "", "5.0.0"},
+	{"CLIENT INFO", "", "6.2.0"},
+	{"CLIENT KILL", "[ip:port] [ID client-id] [TYPE (NORMAL | MASTER | SLAVE | REPLICA | PUBSUB)] [USER username] [ADDR ip:port] [LADDR ip:port] [SKIPME (YES | NO)]", "2.4.0"},
+	{"CLIENT LIST", "[TYPE (NORMAL | MASTER | REPLICA | PUBSUB)] [ID client-id [client-id ...]]", "2.4.0"},
+	{"CLIENT NO-EVICT", "(ON | OFF)", "7.0.0"},
+	{"CLIENT NO-TOUCH", "(ON | OFF)", "7.2.0"},
+	{"CLIENT PAUSE", "timeout [WRITE | ALL]", "2.9.50"},
+	{"CLIENT REPLY", "(ON | OFF | SKIP)", "3.2.0"},
+	{"CLIENT SETINFO", "(LIB-NAME libname | LIB-VER libver)", "7.2.0"},
+	{"CLIENT SETNAME", "connection-name", "2.6.9"},
+	{"CLIENT TRACKING", "(ON | OFF) [REDIRECT client-id] [PREFIX prefix [PREFIX prefix ...]] [BCAST] [OPTIN] [OPTOUT] [NOLOOP]", "6.0.0"},
+	{"CLIENT TRACKINGINFO", "", "6.2.0"},
+	{"CLIENT UNBLOCK", "client-id [TIMEOUT | ERROR]", "5.0.0"},
+	{"CLIENT UNPAUSE", "", "6.2.0"},
+	{"CLUSTER ADDSLOTS", "slot [slot ...]", "3.0.0"},
+	{"CLUSTER ADDSLOTSRANGE", "start-slot end-slot [start-slot end-slot ...]", "7.0.0"},
+	{"CLUSTER BUMPEPOCH", "", "3.0.0"},
+	{"CLUSTER COUNT-FAILURE-REPORTS", "node-id", "3.0.0"},
+	{"CLUSTER COUNTKEYSINSLOT", "slot", "3.0.0"},
+	{"CLUSTER DELSLOTS", "slot [slot ...]", "3.0.0"},
+	{"CLUSTER DELSLOTSRANGE", "start-slot end-slot [start-slot end-slot ...]", "7.0.0"},
+	{"CLUSTER FAILOVER", "[FORCE | TAKEOVER]", "3.0.0"},
+	{"CLUSTER FLUSHSLOTS", "", "3.0.0"},
+	{"CLUSTER FORGET", "node-id", "3.0.0"},
+	{"CLUSTER GETKEYSINSLOT", "slot count", "3.0.0"},
+	{"CLUSTER HELP", "", "5.0.0"},
+	{"CLUSTER INFO", "", "3.0.0"},
+	{"CLUSTER KEYSLOT", "key", "3.0.0"},
+	{"CLUSTER LINKS", "", "7.0.0"},
+	{"CLUSTER MEET", "ip port [cluster-bus-port]", "3.0.0"},
+	{"CLUSTER MYID", "", "3.0.0"},
+	{"CLUSTER MYSHARDID", "", "7.2.0"},
+	{"CLUSTER NODES", "", "3.0.0"},
+	{"CLUSTER REPLICAS", "node-id", "5.0.0"},
+	{"CLUSTER REPLICATE", "node-id", "3.0.0"},
+	{"CLUSTER RESET", "[HARD | SOFT]", "3.0.0"},
+	{"CLUSTER SAVECONFIG", "", "3.0.0"},
+	{"CLUSTER SET-CONFIG-EPOCH", "config-epoch", "3.0.0"},
+	{"CLUSTER SETSLOT", "slot (IMPORTING node-id | MIGRATING node-id | STABLE | NODE node-id)", "3.0.0"},
+	{"CLUSTER SHARDS", "", "7.0.0"},
+	{"CLUSTER SLAVES", "node-id", "3.0.0"},
+	{"CLUSTER SLOTS", "", "3.0.0"},
+	{"COMMAND", "", "2.8.13"},
+	{"COMMAND COUNT", "", "2.8.13"},
+	{"COMMAND DOCS", "[command-name [command-name ...]]", "7.0.0"},
+	{"COMMAND GETKEYS", "command [arg [arg ...]]", "2.8.13"},
+	{"COMMAND GETKEYSANDFLAGS", "command [arg [arg ...]]", "7.0.0"},
+	{"COMMAND HELP", "", "5.0.0"},
+	{"COMMAND INFO", "[command-name [command-name ...]]", "2.8.13"},
+	{"COMMAND LIST", "[FILTERBY (MODULE module-name | ACLCAT category | PATTERN pattern)]", "7.0.0"},
+	{"CONFIG GET", "parameter [parameter ...]", "2.0.0"},
+	{"CONFIG HELP", "", "5.0.0"},
+	{"CONFIG RESETSTAT", "", "2.0.0"},
+	{"CONFIG REWRITE", "", "2.8.0"},
+	{"CONFIG SET", "parameter value [parameter value ...]", "2.0.0"},
+	{"COPY", "source destination [DB destination-db] [REPLACE]", "6.2.0"},
+	{"DBSIZE", "", "1.0.0"},
+	{"DEBUG OBJECT", "key", "1.0.0"},
+	{"DEBUG SEGFAULT", "", "1.0.0"},
+	{"DECR", "key", "1.0.0"},
+	{"DECRBY", "key decrement", "1.0.0"},
+	{"DEL", "key [key ...]", "1.0.0"},
+	{"DISCARD", "", "2.0.0"},
+	{"DUMP", "key", "2.6.0"},
+	{"ECHO", "message", "1.0.0"},
+	{"EVAL", "script numkeys [key [key ...]] [arg [arg ...]]", "2.6.0"},
+	{"EVAL_RO", "script numkeys [key [key ...]] [arg [arg ...]]", "7.0.0"},
+	{"EVALSHA", "sha1 numkeys [key [key ...]] [arg [arg ...]]", "2.6.0"},
+	{"EVALSHA_RO", "sha1 numkeys [key [key ...]] [arg [arg ...]]", "7.0.0"},
+	{"EXEC", "", "1.2.0"},
+	{"EXISTS", "key [key ...]", "1.0.0"},
+	{"EXPIRE", "key seconds [NX | XX | GT | LT]", "1.0.0"},
+	{"EXPIREAT", "key unix-time-seconds [NX | XX | GT | LT]", "1.2.0"},
+	{"EXPIRETIME", "key", "7.0.0"},
+	{"FAILOVER", "[TO host port [FORCE]] [ABORT] [TIMEOUT milliseconds]", "6.2.0"},
+	{"FCALL", "function numkeys [key [key ...]] [arg [arg ...]]", "7.0.0"},
+	{"FCALL_RO", "function numkeys [key [key ...]] [arg [arg ...]]", "7.0.0"},
+	{"FLUSHALL", "[ASYNC | SYNC]", "1.0.0"},
+	{"FLUSHDB", "[ASYNC | SYNC]", "1.0.0"},
+	{"FUNCTION DELETE", "library-name", "7.0.0"},
+	{"FUNCTION DUMP", "", "7.0.0"},
+	{"FUNCTION FLUSH", "[ASYNC | SYNC]", "7.0.0"},
+	{"FUNCTION HELP", "", "7.0.0"},
+	{"FUNCTION KILL", "", "7.0.0"},
+	{"FUNCTION LIST", "[LIBRARYNAME library-name-pattern] [WITHCODE]", "7.0.0"},
+	{"FUNCTION LOAD", "[REPLACE] function-code", "7.0.0"},
+	{"FUNCTION RESTORE", "serialized-value [FLUSH | APPEND | REPLACE]", "7.0.0"},
+	{"FUNCTION STATS", "", "7.0.0"},
+	{"GEOADD", "key [NX | XX] [CH] longitude latitude member [longitude latitude member ...]", "3.2.0"},
+	{"GEODIST", "key member1 member2 [M | KM | FT | MI]", "3.2.0"},
+	{"GEOHASH", "key member [member ...]", "3.2.0"},
+	{"GEOPOS", "key member [member ...]", "3.2.0"},
+	{"GEORADIUS", "key longitude latitude radius (M | KM | FT | MI) [WITHCOORD] [WITHDIST] [WITHHASH] [COUNT count [ANY]] [ASC | DESC] [STORE key] [STOREDIST key]", "3.2.0"},
+	{"GEORADIUS_RO", "key longitude latitude radius (M | KM | FT | MI) [WITHCOORD] [WITHDIST] [WITHHASH] [COUNT count [ANY]] [ASC | DESC]", "3.2.10"},
+	{"GEORADIUSBYMEMBER", "key member radius (M | KM | FT | MI) [WITHCOORD] [WITHDIST] [WITHHASH] [COUNT count [ANY]] [ASC | DESC] [STORE key] [STOREDIST key]", "3.2.0"},
+	{"GEORADIUSBYMEMBER_RO", "key member radius (M | KM | FT | MI) [WITHCOORD] [WITHDIST] [WITHHASH] [COUNT count [ANY]] [ASC | DESC]", "3.2.10"},
+	{"GEOSEARCH", "key [FROMMEMBER member] [FROMLONLAT longitude latitude] [BYRADIUS radius (M | KM | FT | MI)] [BYBOX width height (M | KM | FT | MI)] [ASC | DESC] [COUNT count [ANY]] [WITHCOORD] [WITHDIST] [WITHHASH]", "6.2.0"},
+	{"GEOSEARCHSTORE", "destination source [FROMMEMBER member] [FROMLONLAT longitude latitude] [BYRADIUS radius (M | KM | FT | MI)] [BYBOX width height (M | KM | FT | MI)] [ASC | DESC] [COUNT count [ANY]] [STOREDIST]", "6.2.0"},
+	{"GET", "key", "1.0.0"},
+	{"GETBIT", "key offset", "2.2.0"},
+	{"GETDEL", "key", "6.2.0"},
+	{"GETEX", "key [EX seconds | PX milliseconds | EXAT unix-time-seconds | PXAT unix-time-milliseconds | PERSIST]", "6.2.0"},
+	{"GETRANGE", "key start end", "2.4.0"},
+	{"GETSET", "key value", "1.0.0"},
+	{"HDEL", "key field [field ...]", "2.0.0"},
+	{"HELLO", "[protover [AUTH username password] [SETNAME clientname]]", "6.0.0"},
+	{"HEXISTS", "key field", "2.0.0"},
+	{"HGET", "key field", "2.0.0"},
+	{"HGETALL", "key", "2.0.0"},
+	{"HINCRBY", "key field increment", "2.0.0"},
+	{"HINCRBYFLOAT", "key field increment", "2.6.0"},
+	{"HKEYS", "key", "2.0.0"},
+	{"HLEN", "key", "2.0.0"},
+	{"HMGET", "key field [field ...]", "2.0.0"},
+	{"HMSET", "key field value [field value ...]", "2.0.0"},
+	{"HRANDFIELD", "key [count [WITHVALUES]]", "6.2.0"},
+	{"HSCAN", "key cursor [MATCH pattern] [COUNT count]", "2.8.0"},
+	{"HSET", "key field value [field value ...]", "2.0.0"},
+	{"HSETNX", "key field value", "2.0.0"},
+	{"HSTRLEN", "key field", "3.2.0"},
+	{"HVALS", "key", "2.0.0"},
+	{"INCR", "key", "1.0.0"},
+	{"INCRBY", "key increment", "1.0.0"},
+	{"INCRBYFLOAT", "key increment", "2.6.0"},
+	{"INFO", "[section [section ...]]", "1.0.0"},
+	{"KEYS", "pattern", "1.0.0"},
+	{"LASTSAVE", "", "1.0.0"},
+	{"LATENCY DOCTOR", "", "2.8.13"},
+	{"LATENCY GRAPH", "event", "2.8.13"},
+	{"LATENCY HELP", "", "2.8.13"},
+	{"LATENCY HISTOGRAM", "[command [command ...]]", "7.0.0"},
+	{"LATENCY HISTORY", "event", "2.8.13"},
+	{"LATENCY LATEST", "", "2.8.13"},
+	{"LATENCY RESET", "[event [event ...]]", "2.8.13"},
+	{"LCS", "key1 key2 [LEN] [IDX] [MINMATCHLEN min-match-len] [WITHMATCHLEN]", "7.0.0"},
+	{"LINDEX", "key index", "1.0.0"},
+	{"LINSERT", "key (BEFORE | AFTER) pivot element", "2.2.0"},
+	{"LLEN", "key", "1.0.0"},
+	{"LMOVE", "source destination (LEFT | RIGHT) (LEFT | RIGHT)", "6.2.0"},
+	{"LMPOP", "numkeys key [key ...] (LEFT | RIGHT) [COUNT count]", "7.0.0"},
+	{"LOLWUT", "[VERSION version]", "5.0.0"},
+	{"LPOP", "key [count]", "1.0.0"},
+	{"LPOS", "key element [RANK rank] [COUNT num-matches] [MAXLEN len]", "6.0.6"},
+	{"LPUSH", "key element [element ...]", "1.0.0"},
+	{"LPUSHX", "key element [element ...]", "2.2.0"},
+	{"LRANGE", "key start stop", "1.0.0"},
+	{"LREM", "key count element", "1.0.0"},
+	{"LSET", "key index element", "1.0.0"},
+	{"LTRIM", "key start stop", "1.0.0"},
+	{"MEMORY DOCTOR", "", "4.0.0"},
+	{"MEMORY HELP", "", "4.0.0"},
+	{"MEMORY MALLOC-STATS", "", "4.0.0"},
+	{"MEMORY PURGE", "", "4.0.0"},
+	{"MEMORY STATS", "", "4.0.0"},
+	{"MEMORY USAGE", "key [SAMPLES count]", "4.0.0"},
+	{"MGET", "key [key ...]", "1.0.0"},
+	{"MIGRATE", "host port (key | \"\") destination-db timeout [COPY] [REPLACE] [AUTH password] [AUTH2 username password] [KEYS key [key ...]]", "2.6.0"},
+	{"MODULE HELP", "", "5.0.0"},
+	{"MODULE LIST", "", "4.0.0"},
+	{"MODULE LOAD", "path [arg [arg ...]]", "4.0.0"},
+	{"MODULE LOADEX", "path [CONFIG name value [CONFIG name value ...]] [ARGS args [args ...]]", "7.0.0"},
+	{"MODULE UNLOAD", "name", "4.0.0"},
+	{"MONITOR", "", "1.0.0"},
+	{"MOVE", "key db", "1.0.0"},
+	{"MSET", "key value [key value ...]", "1.0.1"},
+	{"MSETNX", "key value [key value ...]", "1.0.1"},
+	{"MULTI", "", "1.2.0"},
+	{"OBJECT ENCODING", "key", "2.2.3"},
+	{"OBJECT FREQ", "key", "4.0.0"},
+	{"OBJECT HELP", "", "6.2.0"},
+	{"OBJECT IDLETIME", "key", "2.2.3"},
+	{"OBJECT REFCOUNT", "key", "2.2.3"},
+	{"PERSIST", "key", "2.2.0"},
+	{"PEXPIRE", "key milliseconds [NX | XX | GT | LT]", "2.6.0"},
+	{"PEXPIREAT", "key unix-time-milliseconds [NX | XX | GT | LT]", "2.6.0"},
+	{"PEXPIRETIME", "key", "7.0.0"},
+	{"PFADD", "key [element [element ...]]", "2.8.9"},
+	{"PFCOUNT", "key [key ...]", "2.8.9"},
+	{"PFMERGE", "destkey [sourcekey [sourcekey ...]]", "2.8.9"},
+	{"PING", "[message]", "1.0.0"},
+	{"PSETEX", "key milliseconds value", "2.6.0"},
+	{"PSUBSCRIBE", "pattern [pattern ...]", "2.0.0"},
+	{"PSYNC", "replicationid offset", "2.8.0"},
+	{"PTTL", "key", "2.6.0"},
+	{"PUBLISH", "channel message", "2.0.0"},
+	{"PUBSUB CHANNELS", "[pattern]", "2.8.0"},
+	{"PUBSUB HELP", "", "6.2.0"},
+	{"PUBSUB NUMPAT", "", "2.8.0"},
+	{"PUBSUB NUMSUB", "[channel [channel ...]]", "2.8.0"},
+	{"PUBSUB SHARDCHANNELS", "[pattern]", "7.0.0"},
+	{"PUBSUB SHARDNUMSUB", "[shardchannel [shardchannel ...]]", "7.0.0"},
+	{"PUNSUBSCRIBE", "[pattern [pattern ...]]", "2.0.0"},
+	{"QUIT", "", "1.0.0"},
+	{"RANDOMKEY", "", "1.0.0"},
+	{"READONLY", "", "3.0.0"},
+	{"READWRITE", "", "3.0.0"},
+	{"RENAME", "key newkey", "1.0.0"},
+	{"RENAMENX", "key newkey", "1.0.0"},
+	{"REPLICAOF", "host port", "5.0.0"},
+	{"RESET", "", "6.2.0"},
+	{"RESTORE", "key ttl serialized-value [REPLACE] [ABSTTL] [IDLETIME seconds] [FREQ frequency]", "2.6.0"},
+	{"ROLE", "", "2.8.12"},
+	{"RPOP", "key [count]", "1.0.0"},
+	{"RPOPLPUSH", "source destination", "1.2.0"},
+	{"RPUSH", "key element [element ...]", "1.0.0"},
+	{"RPUSHX", "key element [element ...]", "2.2.0"},
+	{"SADD", "key member [member ...]", "1.0.0"},
+	{"SAVE", "", "1.0.0"},
+	{"SCAN", "cursor [MATCH pattern] [COUNT count] [TYPE type]", "2.8.0"},
+	{"SCARD", "key", "1.0.0"},
+	{"SCRIPT DEBUG", "(YES | SYNC | NO)", "3.2.0"},
+	{"SCRIPT EXISTS", "sha1 [sha1 ...]", "2.6.0"},
+	{"SCRIPT FLUSH", "[ASYNC | SYNC]", "2.6.0"},
+	{"SCRIPT HELP", "", "5.0.0"},
+	{"SCRIPT KILL", "", "2.6.0"},
+	{"SCRIPT LOAD", "script", "2.6.0"},
+	{"SDIFF", "key [key ...]", "1.0.0"},
+	{"SDIFFSTORE", "destination key [key ...]", "1.0.0"},
+	{"SELECT", "index", "1.0.0"},
+	{"SET", "key value [NX | XX] [GET] [EX seconds | PX milliseconds | EXAT unix-time-seconds | PXAT unix-time-milliseconds | KEEPTTL]", "1.0.0"},
+	{"SETBIT", "key offset value", "2.2.0"},
+	{"SETEX", "key seconds value", "2.0.0"},
+	{"SETNX", "key value", "1.0.0"},
+	{"SETRANGE", "key offset value", "2.2.0"},
+	{"SHUTDOWN", "[NOSAVE | SAVE] [NOW] [FORCE] [ABORT]", "1.0.0"},
+	{"SINTER", "key [key ...]", "1.0.0"},
+	{"SINTERCARD", "numkeys key [key ...] [LIMIT limit]", "7.0.0"},
+	{"SINTERSTORE", "destination key [key ...]", "1.0.0"},
+	{"SISMEMBER", "key member", "1.0.0"},
+	{"SLAVEOF", "host port", "1.0.0"},
+	{"SLOWLOG GET", "[count]", "2.2.12"},
+	{"SLOWLOG HELP", "", "6.2.0"},
+	{"SLOWLOG LEN", "", "2.2.12"},
+	{"SLOWLOG RESET", "", "2.2.12"},
+	{"SMEMBERS", "key", "1.0.0"},
+	{"SMISMEMBER", "key member [member ...]", "6.2.0"},
+	{"SMOVE", "source destination member", "1.0.0"},
+	{"SORT", "key [BY pattern] [LIMIT offset count] [GET pattern [GET pattern ...]] [ASC | DESC] [ALPHA] [STORE destination]", "1.0.0"},
+	{"SORT_RO", "key [BY pattern] [LIMIT offset count] [GET pattern [GET pattern ...]] [ASC | DESC] [ALPHA]", "7.0.0"},
+	{"SPOP", "key [count]", "1.0.0"},
+	{"SPUBLISH", "shardchannel message", "7.0.0"},
+	{"SRANDMEMBER", "key [count]", "1.0.0"},
+	{"SREM", "key member [member ...]", "1.0.0"},
+	{"SSCAN", "key cursor [MATCH pattern] [COUNT count]", "2.8.0"},
+	{"SSUBSCRIBE", "shardchannel [shardchannel ...]", "7.0.0"},
+	{"STRALGO", "LCS algo-specific-argument [algo-specific-argument ...]", "6.0.0"},
+	{"STRLEN", "key", "2.2.0"},
+	{"SUBSCRIBE", "channel [channel ...]", "2.0.0"},
+	{"SUNION", "key [key ...]", "1.0.0"},
+	{"SUNIONSTORE", "destination key [key ...]", "1.0.0"},
+	{"SUNSUBSCRIBE", "[shardchannel [shardchannel ...]]", "7.0.0"},
+	{"SWAPDB", "index1 index2", "4.0.0"},
+	{"SYNC", "", "1.0.0"},
+	{"TIME", "", "2.6.0"},
+	{"TOUCH", "key [key ...]", "3.2.1"},
+	{"TTL", "key", "1.0.0"},
+	{"TYPE", "key", "1.0.0"},
+	{"UNLINK", "key [key ...]", "4.0.0"},
+	{"UNSUBSCRIBE", "[channel [channel ...]]", "2.0.0"},
+	{"UNWATCH", "", "2.2.0"},
+	{"WAIT", "numreplicas timeout", "3.0.0"},
+	{"WAITAOF", "numlocal numreplicas timeout", "7.2.0"},
+	{"WATCH", "key [key ...]", "2.2.0"},
+	{"XACK", "key group id [id ...]", "5.0.0"},
+	{"XADD", "key [NOMKSTREAM] [(MAXLEN | MINID) [= | ~] threshold [LIMIT count]] (* | id) field value [field value ...]", "5.0.0"},
+	{"XAUTOCLAIM", "key group consumer min-idle-time start [COUNT count] [JUSTID]", "6.2.0"},
+	{"XCLAIM", "key group consumer min-idle-time id [id ...] [IDLE ms] [TIME unix-time-milliseconds] [RETRYCOUNT count] [FORCE] [JUSTID] [LASTID lastid]", "5.0.0"},
+	{"XDEL", "key id [id ...]", "5.0.0"},
+	{"XGROUP CREATE", "key group (id | $) [MKSTREAM] [ENTRIESREAD entries-read]", "5.0.0"},
+	{"XGROUP CREATECONSUMER", "key group consumer", "6.2.0"},
+	{"XGROUP DELCONSUMER", "key group consumer", "5.0.0"},
+	{"XGROUP DESTROY", "key group", "5.0.0"},
+	{"XGROUP HELP", "", "5.0.0"},
+	{"XGROUP SETID", "key group (id | $) [ENTRIESREAD entries-read]", "5.0.0"},
+	{"XINFO CONSUMERS", "key group", "5.0.0"},
+	{"XINFO GROUPS", "key", "5.0.0"},
+	{"XINFO HELP", "", "5.0.0"},
+	{"XINFO STREAM", "key [FULL [COUNT count]]", "5.0.0"},
+	{"XLEN", "key", "5.0.0"},
+	{"XPENDING", "key group [[IDLE min-idle-time] start end count [consumer]]", "5.0.0"},
+	{"XRANGE", "key start end [COUNT count]", "5.0.0"},
+	{"XREAD", "[COUNT count] [BLOCK milliseconds] STREAMS key [key ...] id [id ...]", "5.0.0"},
+	{"XREADGROUP", "GROUP group consumer [COUNT count] [BLOCK milliseconds] [NOACK] STREAMS key [key ...] id [id ...]", "5.0.0"},
+	{"XREVRANGE", "key end start [COUNT count]", "5.0.0"},
+	{"XSETID", "key last-id [ENTRIESADDED entries-added] [MAXDELETEDID max-deleted-id]", "5.0.0"},
+	{"XTRIM", "key (MAXLEN | MINID) [= | ~] threshold [LIMIT count]", "5.0.0"},
+	{"ZADD", "key [NX | XX] [GT | LT] [CH] [INCR] score member [score member ...]", "1.2.0"},
+	{"ZCARD", "key", "1.2.0"},
+	{"ZCOUNT", "key min max", "2.0.0"},
+	{"ZDIFF", "numkeys key [key ...] [WITHSCORES]", "6.2.0"},
+	{"ZDIFFSTORE", "destination numkeys key [key ...]", "6.2.0"},
+	{"ZINCRBY", "key increment member", "1.2.0"},
+	{"ZINTER", "numkeys key [key ...] [WEIGHTS weight [weight ...]] [AGGREGATE (SUM | MIN | MAX)] [WITHSCORES]", "6.2.0"},
+	{"ZINTERCARD", "numkeys key [key ...] [LIMIT limit]", "7.0.0"},
+	{"ZINTERSTORE", "destination numkeys key [key ...] [WEIGHTS weight [weight ...]] [AGGREGATE (SUM | MIN | MAX)]", "2.0.0"},
+	{"ZLEXCOUNT", "key min max", "2.8.9"},
+	{"ZMPOP", "numkeys key [key ...] (MIN | MAX) [COUNT count]", "7.0.0"},
+	{"ZMSCORE", "key member [member ...]", "6.2.0"},
+	{"ZPOPMAX", "key [count]", "5.0.0"},
+	{"ZPOPMIN", "key [count]", "5.0.0"},
+	{"ZRANDMEMBER", "key [count [WITHSCORES]]", "6.2.0"},
+	{"ZRANGE", "key min max [BYSCORE | BYLEX] [REV] [LIMIT offset count] [WITHSCORES]", "1.2.0"},
+	{"ZRANGEBYLEX", "key min max [LIMIT offset count]", "2.8.9"},
+	{"ZRANGEBYSCORE", "key min max [WITHSCORES] [LIMIT offset count]", "1.0.5"},
+	{"ZRANGESTORE", "dst src min max [BYSCORE | BYLEX] [REV] [LIMIT offset count]", "6.2.0"},
+	{"ZRANK", "key member [WITHSCORE]", "2.0.0"},
+	{"ZREM", "key member [member ...]", "1.2.0"},
+	{"ZREMRANGEBYLEX", "key min max", "2.8.9"},
+	{"ZREMRANGEBYRANK", "key start stop", "2.0.0"},
+	{"ZREMRANGEBYSCORE", "key min max", "1.2.0"},
+	{"ZREVRANGE", "key start stop [WITHSCORES]", "1.2.0"},
+	{"ZREVRANGEBYLEX", "key max min [LIMIT offset count]", "2.8.9"},
+	{"ZREVRANGEBYSCORE", "key max min [WITHSCORES] [LIMIT offset count]", "2.2.0"},
+	{"ZREVRANK", "key member [WITHSCORE]", "2.0.0"},
+	{"ZSCAN", "key cursor [MATCH pattern] [COUNT count]", "2.8.0"},
+	{"ZSCORE", "key member", "1.2.0"},
+	{"ZUNION", "numkeys key [key ...] [WEIGHTS weight [weight ...]] [AGGREGATE (SUM | MIN | MAX)] [WITHSCORES]", "6.2.0"},
+	{"ZUNIONSTORE", "destination numkeys key [key ...] [WEIGHTS weight [weight ...]] [AGGREGATE (SUM | MIN | MAX)]", "2.0.0"},
 }
 
 func GetCommands() []string {
-	return commands
+	var names []string
+	for _, c := range commands {
+		names = append(names, c.Name)
+	}
+
+	return names
 }
 
-func getCommands(text string) []string {
-	var filtered []string
+func getCommands(text string) []Command {
+	text = strings.ToUpper(text)
+
+	var filtered []Command
 	for _, c := range commands {
-		if strings.HasPrefix(c, text) {
+		if strings.HasPrefix(c.Name, text) {
 			filtered = append(filtered, c)
 		}
 	}
 
 	return filtered
 }
+
+func getCommand(name string) (Command, bool) {
+	for _, c := range commands {
+		if c.Name == name {
+			return c, true
+		}
+	}
+
+	return Command{}, false
+}
+
+// CommandArguments returns the syntax tree of the arguments of the command.
+func CommandArguments(name string) ([]*Argument, bool) {
+	c, ok := getCommand(name)
+	if !ok {
+		return nil, false
+	}
+
+	return ParseArguments(c.Arguments), true
+}
+
+// Signature returns the command with its arguments (e.g. "GET key").
+func (c Command) Signature() string {
+	if c.Arguments == "" {
+		return c.Name
+	}
+
+	return c.Name + " " + c.Arguments
+}
+
+// IsContainer reports whether the command only exists with a subcommand (e.g. CONFIG in CONFIG GET).
+func IsContainer(name string) bool {
+	_, ok := getCommand(name)
+	return !ok && len(getCommands(name+" ")) > 0
+}
+
+// SimilarCommand returns the command of the catalogue closest to the name (e.g. "GET" for "GTE")
+// or, for a container and a subcommand (e.g. "CONFIG GTE"), the closest subcommand of the container.
+// It returns false when no command is close enough for the name to be a typo of it.
+func SimilarCommand(name string) (string, bool) {
+	name = strings.ToUpper(name)
+
+	candidates, word := commands, name
+	if i := strings.IndexByte(name, ' '); i != -1 {
+		candidates, word = getCommands(name[:i+1]), name[i+1:]
+	}
+
+	// one typo every three letters (e.g. HGETAL) but at least one for short commands (e.g. GTE)
+	maxDistance := len(word) / 3
+	if maxDistance == 0 {
+		maxDistance = 1
+	}
+
+	best, bestDistance := "", maxDistance+1
+	for _, c := range candidates {
+		if distance := editDistance(name, c.Name); distance < bestDistance {
+			best, bestDistance = c.Name, distance
+		}
+	}
+
+	return best, best != ""
+}
+
+// editDistance returns the number of insertions, deletions, substitutions and transpositions of adjacent letters
+// needed to change a into b.
+func editDistance(a string, b string) int {
+	previous2 := make([]int, len(b)+1)
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				current[j] = min(current[j], previous2[j-2]+1)
+			}
+		}
+
+		previous2, previous, current = previous, current, previous2
+	}
+
+	return previous[len(b)]
+}
+
+func min(values ...int) int {
+	m := values[0]
+	for _, v := range values[1:] {
+		if v < m {
+			m = v
+		}
+	}
+
+	return m
+}
@@ -1,33 +1,338 @@
 package completer
 
 import (
+	"fmt"
+	"sort"
+	"strings"
+
 	"github.com/fagnercarvalho/redis-lsp/ast"
-	"github.com/fagnercarvalho/redis-lsp/token"
+	"github.com/fagnercarvalho/redis-lsp/variables"
+)
+
+type ItemKind int
+
+const (
+	CommandItem ItemKind = iota
+	KeyItem
+	FieldItem
+	OptionItem
+	UserItem
+	VariableItem
+	FunctionItem
+	ConstantItem
+)
+
+type Item struct {
+	Label  string
+	Kind   ItemKind
+	Detail string
+
+	// Snippet is the text to be inserted with tab stops for the required arguments (e.g. "GET ${1:key}").
+	// It is empty when the item has no arguments.
+	Snippet string
+}
+
+// LetterCase is how commands and options are written when completed.
+type LetterCase string
+
+const (
+	UpperCase LetterCase = "upper"
+	LowerCase LetterCase = "lower"
+	// AsTyped uses lower case if the user is typing in lower case and upper case otherwise.
+	AsTyped LetterCase = "as-typed"
 )
 
 type Completer struct {
 	Users []string
-	Keys []string
+	Keys  []string
+	Case  LetterCase
+
+	// Fields returns the fields of a hash, it is nil when there is no Redis to fetch them from.
+	Fields func(key string) []string
+
+	// Environment returns the variables defined outside of the document (e.g. in the .env file).
+	Environment func() map[string]string
+
+	// Functions returns the functions that can be called with FCALL with the library of each one
+	// (e.g. from FUNCTION LIST and the library files of the workspace).
+	Functions func() map[string]string
+
+	// TargetVersion is the Redis version the commands are sent to (e.g. "6.2"),
+	// commands and options added after it are not completed. Every command is completed when it is empty.
+	TargetVersion string
+}
+
+// commands that have hash fields as arguments with the index of the first field and the step between fields
+// (a step of 0 means there is only one field)
+var fieldCommands = map[string][2]int{
+	"HDEL":         {1, 1},
+	"HEXISTS":      {1, 0},
+	"HGET":         {1, 0},
+	"HINCRBY":      {1, 0},
+	"HINCRBYFLOAT": {1, 0},
+	"HMGET":        {1, 1},
+	"HMSET":        {1, 2},
+	"HSET":         {1, 2},
+	"HSETNX":       {1, 0},
+	"HSTRLEN":      {1, 0},
 }
 
-func (c Completer) Complete(text string, line int, position int) []string {
-	tokenizer := token.Tokenizer{}
-	tokens := tokenizer.Tokenize(text)
+var userCommands = []string{"ACL GETUSER", "ACL SETUSER", "ACL DELUSER"}
+
+var functionCommands = []string{"FCALL", "FCALL_RO"}
+
+// Complete returns the completion items for the given line and position
+// and the text before the position that should be replaced by the item (e.g. "ACL GE" or "NX").
+func (c Completer) Complete(text string, line int, position int) ([]Item, string) {
+	statements := ast.Parse(text)
+	if len(statements) == 0 {
+		return c.commandItems(c.available(getCommands("")), ""), ""
+	}
 
-	var redisTokens []ast.RedisToken
-	for _, t := range tokens {
-		token := ast.NewToken(t)
-		redisTokens = append(redisTokens, token)
+	// the cursor is inside the Lua script of an EVAL
+	if items, typed, ok := c.scriptCompletion(statements, line, position); ok {
+		return items, typed
 	}
 
-	statements := ast.New(redisTokens)
 	statement, endIndex := ast.GetSelectedStatement(statements, line, position-1)
+	typed := strings.TrimLeft(statement.String()[:endIndex], " \t")
+	if typed == "" || strings.HasSuffix(typed, ";") {
+		return c.commandItems(c.available(getCommands("")), ""), ""
+	}
+
+	matches := c.available(getCommands(typed))
+	if len(matches) > 0 {
+		return c.commandItems(matches, typed), typed
+	}
+
+	typedStatements := ast.Parse(typed)
+	typedStatement := typedStatements[len(typedStatements)-1]
+
+	// there is nothing to complete inside a comment
+	if ast.GetComment(typedStatement) != nil {
+		return nil, ""
+	}
 
-	if statement.PreviousTokenIs([]string{"ACL GETUSER"}, position-1) {
-		return c.Users
-	} else if  statement.PreviousTokenIs([]string{"GET", "SET"}, position-1) {
-		return c.Keys
+	var arguments []string
+	for _, a := range typedStatement.GetArguments() {
+		arguments = append(arguments, a.String())
+	}
+
+	current := ""
+	if !strings.HasSuffix(typed, " ") && len(arguments) > 0 {
+		current = arguments[len(arguments)-1]
 	} else {
-		return getCommands(statement.String()[:endIndex])
+		arguments = append(arguments, current)
+	}
+
+	// a variable is being typed (e.g. "user:${TEN")
+	if i := strings.LastIndex(current, "${"); i != -1 && !strings.Contains(current[i:], "}") {
+		return c.variableItems(statements, current[i+2:]), current[i+2:]
+	}
+
+	items := c.argumentItems(ast.CommandName(typedStatement), arguments, len(arguments)-1)
+
+	return c.filterItems(items, current), current
+}
+
+// filterItems returns the items starting with what is being typed.
+func (c Completer) filterItems(items []Item, current string) []Item {
+	var filtered []Item
+	for _, item := range items {
+		// keys, fields and users are case-sensitive but options are not
+		if item.Kind == OptionItem {
+			if strings.HasPrefix(item.Label, strings.ToUpper(current)) {
+				item.Label = c.applyCase(item.Label, current)
+				filtered = append(filtered, item)
+			}
+
+			continue
+		}
+
+		if strings.HasPrefix(item.Label, current) {
+			filtered = append(filtered, item)
+		}
+	}
+
+	return filtered
+}
+
+func (c Completer) argumentItems(command string, arguments []string, index int) []Item {
+	if IsKey(command, arguments, index) {
+		return valueItems(c.Keys, KeyItem, "key")
+	}
+
+	for _, u := range userCommands {
+		if u == command && index == 0 {
+			return valueItems(c.Users, UserItem, "user")
+		}
+	}
+
+	for _, f := range functionCommands {
+		if f == command && index == 0 && c.Functions != nil {
+			return functionItems(c.Functions())
+		}
+	}
+
+	if field, ok := fieldCommands[command]; ok && c.Fields != nil && index >= field[0] {
+		if index == field[0] || (field[1] > 0 && (index-field[0])%field[1] == 0) {
+			return valueItems(c.Fields(arguments[0]), FieldItem, fmt.Sprintf("field of %v", arguments[0]))
+		}
 	}
+
+	cmd, ok := getCommand(command)
+	if !ok {
+		return nil
+	}
+
+	used := map[string]bool{}
+	for _, a := range arguments[:index] {
+		used[strings.ToUpper(a)] = true
+	}
+
+	var items []Item
+	for _, o := range Options(ParseArguments(cmd.Arguments)) {
+		if used[o] {
+			continue
+		}
+
+		if since, ok := OptionSince(command, o); ok && !Available(since, c.TargetVersion) {
+			continue
+		}
+
+		items = append(items, Item{Label: o, Kind: OptionItem, Detail: cmd.Signature()})
+	}
+
+	return items
+}
+
+func (c Completer) commandItems(commands []Command, typed string) []Item {
+	var items []Item
+	for _, command := range commands {
+		item := Item{
+			Label:   c.applyCase(command.Name, typed),
+			Kind:    CommandItem,
+			Detail:  fmt.Sprintf("%v (since %v)", command.Signature(), command.Since),
+			Snippet: snippet(command),
+		}
+
+		if item.Snippet != "" {
+			item.Snippet = item.Label + strings.TrimPrefix(item.Snippet, command.Name)
+		}
+
+		items = append(items, item)
+	}
+
+	return items
+}
+
+// applyCase changes the case of a command or option according to the configured case and what was typed.
+func (c Completer) applyCase(value string, typed string) string {
+	switch c.Case {
+	case LowerCase:
+		return strings.ToLower(value)
+	case UpperCase:
+		return value
+	}
+
+	if strings.ContainsAny(typed, "abcdefghijklmnopqrstuvwxyz") && strings.ToLower(typed) == typed {
+		return strings.ToLower(value)
+	}
+
+	return value
+}
+
+func (c Completer) variableItems(statements []ast.TokenList, typed string) []Item {
+	resolver := variables.Resolver{Definitions: variables.Definitions(statements)}
+	if c.Environment != nil {
+		resolver.EnvFile = c.Environment()
+	}
+
+	var items []Item
+	for _, name := range resolver.Names() {
+		if strings.HasPrefix(name, typed) {
+			items = append(items, Item{Label: name, Kind: VariableItem, Detail: "variable"})
+		}
+	}
+
+	return items
+}
+
+// functionItems returns the functions sorted by name with the library of each one.
+func functionItems(functions map[string]string) []Item {
+	var names []string
+	for name := range functions {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	var items []Item
+	for _, name := range names {
+		items = append(items, Item{Label: name, Kind: FunctionItem, Detail: fmt.Sprintf("function of library %v", functions[name])})
+	}
+
+	return items
+}
+
+func valueItems(values []string, kind ItemKind, detail string) []Item {
+	var items []Item
+	for _, v := range values {
+		if v == "" {
+			continue
+		}
+
+		items = append(items, Item{Label: v, Kind: kind, Detail: detail})
+	}
+
+	return items
+}
+
+// snippet returns the command with tab stops for each required argument
+// (e.g. "LMOVE ${1:source} ${2:destination} ${3|LEFT,RIGHT|} ${4|LEFT,RIGHT|}").
+func snippet(c Command) string {
+	parts := []string{c.Name}
+	stop := 1
+	var add func(arguments []*Argument)
+	add = func(arguments []*Argument) {
+		for _, a := range arguments {
+			if a.Optional {
+				continue
+			}
+
+			if !a.IsGroup() {
+				if a.Token {
+					parts = append(parts, a.Name)
+				} else {
+					parts = append(parts, fmt.Sprintf("${%v:%v}", stop, a.Name))
+					stop++
+				}
+
+				continue
+			}
+
+			var choices []string
+			for _, choice := range a.Choices {
+				if len(choice) == 1 && !choice[0].IsGroup() {
+					choices = append(choices, choice[0].Name)
+				}
+			}
+
+			if len(choices) == len(a.Choices) {
+				parts = append(parts, fmt.Sprintf("${%v|%v|}", stop, strings.Join(choices, ",")))
+				stop++
+				continue
+			}
+
+			add(a.Choices[0])
+		}
+	}
+
+	add(ParseArguments(c.Arguments))
+
+	if stop == 1 {
+		return ""
+	}
+
+	return strings.Join(parts, " ")
 }
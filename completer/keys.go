@@ -0,0 +1,350 @@
+package completer
+
+import (
+	"strconv"
+	"strings"
+)
+
+// keyFlags are the flags of a key spec that say how the command uses its keys, as in the COMMAND DOCS output.
+type keyFlags int
+
+const (
+	// ro keys are only read
+	ro keyFlags = 1 << iota
+	// rw keys are read and modified
+	rw
+	// ow keys are overwritten without being read
+	ow
+	// rm keys are removed
+	rm
+)
+
+// keySpec describes where the keys are in the arguments of a command, the same way the COMMAND command does.
+// Arguments are indexed from 1 and a negative last index is relative to the end of the arguments
+// (e.g. -1 is the last argument, -2 is the one before it).
+// When numKeys is set, the argument in that index holds the amount of keys that come right after it.
+// A command has a key spec for each group of keys used in a different way (e.g. the destination and the sources of SUNIONSTORE).
+type keySpec struct {
+	first   int
+	last    int
+	step    int
+	numKeys int
+	flags   keyFlags
+}
+
+// keywordKeySpec is a key that comes right after a keyword (e.g. the destination after STORE in SORT)
+// or, when half is set, the first half of the arguments after the keyword (e.g. the streams after STREAMS in XREAD,
+// which are followed by an ID for each stream).
+type keywordKeySpec struct {
+	keyword string
+	flags   keyFlags
+	half    bool
+}
+
+// keywordKeySpecs are the keys of the commands that are found by a keyword instead of a position.
+var keywordKeySpecs = map[string][]keywordKeySpec{
+	"GEORADIUS":         {{"STORE", ow, false}, {"STOREDIST", ow, false}},
+	"GEORADIUSBYMEMBER": {{"STORE", ow, false}, {"STOREDIST", ow, false}},
+	"SORT":              {{"STORE", ow, false}},
+	// the entries of the streams are only read, XREADGROUP changes the consumer group instead
+	"XREAD":      {{"STREAMS", ro, true}},
+	"XREADGROUP": {{"STREAMS", ro, true}},
+}
+
+// keySpecs are the positional keys of the commands, PFCOUNT keys are modified since it caches the cardinality in them.
+var keySpecs = map[string][]keySpec{
+	"APPEND":                {{1, 1, 1, 0, rw}},
+	"BITCOUNT":              {{1, 1, 1, 0, ro}},
+	"BITFIELD":              {{1, 1, 1, 0, rw}},
+	"BITFIELD_RO":           {{1, 1, 1, 0, ro}},
+	"BITOP":                 {{2, 2, 1, 0, ow}, {3, -1, 1, 0, ro}},
+	"BITPOS":                {{1, 1, 1, 0, ro}},
+	"BLMOVE":                {{1, 2, 1, 0, rw}},
+	"BLMPOP":                {{0, 0, 0, 2, rw}},
+	"BLPOP":                 {{1, -2, 1, 0, rw}},
+	"BRPOP":                 {{1, -2, 1, 0, rw}},
+	"BRPOPLPUSH":            {{1, 2, 1, 0, rw}},
+	"BZMPOP":                {{0, 0, 0, 2, rw}},
+	"BZPOPMAX":              {{1, -2, 1, 0, rw}},
+	"BZPOPMIN":              {{1, -2, 1, 0, rw}},
+	"COPY":                  {{1, 1, 1, 0, ro}, {2, 2, 1, 0, ow}},
+	"DEBUG OBJECT":          {{1, 1, 1, 0, ro}},
+	"DECR":                  {{1, 1, 1, 0, rw}},
+	"DECRBY":                {{1, 1, 1, 0, rw}},
+	"DEL":                   {{1, -1, 1, 0, rm}},
+	"DUMP":                  {{1, 1, 1, 0, ro}},
+	"EVAL":                  {{0, 0, 0, 2, rw}},
+	"EVALSHA":               {{0, 0, 0, 2, rw}},
+	"EVALSHA_RO":            {{0, 0, 0, 2, ro}},
+	"EVAL_RO":               {{0, 0, 0, 2, ro}},
+	"EXISTS":                {{1, -1, 1, 0, ro}},
+	"EXPIRE":                {{1, 1, 1, 0, rw}},
+	"EXPIREAT":              {{1, 1, 1, 0, rw}},
+	"EXPIRETIME":            {{1, 1, 1, 0, ro}},
+	"FCALL":                 {{0, 0, 0, 2, rw}},
+	"FCALL_RO":              {{0, 0, 0, 2, ro}},
+	"GEOADD":                {{1, 1, 1, 0, rw}},
+	"GEODIST":               {{1, 1, 1, 0, ro}},
+	"GEOHASH":               {{1, 1, 1, 0, ro}},
+	"GEOPOS":                {{1, 1, 1, 0, ro}},
+	"GEORADIUS":             {{1, 1, 1, 0, ro}},
+	"GEORADIUSBYMEMBER":     {{1, 1, 1, 0, ro}},
+	"GEORADIUSBYMEMBER_RO":  {{1, 1, 1, 0, ro}},
+	"GEORADIUS_RO":          {{1, 1, 1, 0, ro}},
+	"GEOSEARCH":             {{1, 1, 1, 0, ro}},
+	"GEOSEARCHSTORE":        {{1, 1, 1, 0, ow}, {2, 2, 1, 0, ro}},
+	"GET":                   {{1, 1, 1, 0, ro}},
+	"GETBIT":                {{1, 1, 1, 0, ro}},
+	"GETDEL":                {{1, 1, 1, 0, rw | rm}},
+	"GETEX":                 {{1, 1, 1, 0, rw}},
+	"GETRANGE":              {{1, 1, 1, 0, ro}},
+	"GETSET":                {{1, 1, 1, 0, rw}},
+	"HDEL":                  {{1, 1, 1, 0, rw}},
+	"HEXISTS":               {{1, 1, 1, 0, ro}},
+	"HGET":                  {{1, 1, 1, 0, ro}},
+	"HGETALL":               {{1, 1, 1, 0, ro}},
+	"HINCRBY":               {{1, 1, 1, 0, rw}},
+	"HINCRBYFLOAT":          {{1, 1, 1, 0, rw}},
+	"HKEYS":                 {{1, 1, 1, 0, ro}},
+	"HLEN":                  {{1, 1, 1, 0, ro}},
+	"HMGET":                 {{1, 1, 1, 0, ro}},
+	"HMSET":                 {{1, 1, 1, 0, rw}},
+	"HRANDFIELD":            {{1, 1, 1, 0, ro}},
+	"HSCAN":                 {{1, 1, 1, 0, ro}},
+	"HSET":                  {{1, 1, 1, 0, rw}},
+	"HSETNX":                {{1, 1, 1, 0, rw}},
+	"HSTRLEN":               {{1, 1, 1, 0, ro}},
+	"HVALS":                 {{1, 1, 1, 0, ro}},
+	"INCR":                  {{1, 1, 1, 0, rw}},
+	"INCRBY":                {{1, 1, 1, 0, rw}},
+	"INCRBYFLOAT":           {{1, 1, 1, 0, rw}},
+	"LCS":                   {{1, 2, 1, 0, ro}},
+	"LINDEX":                {{1, 1, 1, 0, ro}},
+	"LINSERT":               {{1, 1, 1, 0, rw}},
+	"LLEN":                  {{1, 1, 1, 0, ro}},
+	"LMOVE":                 {{1, 2, 1, 0, rw}},
+	"LMPOP":                 {{0, 0, 0, 1, rw}},
+	"LPOP":                  {{1, 1, 1, 0, rw}},
+	"LPOS":                  {{1, 1, 1, 0, ro}},
+	"LPUSH":                 {{1, 1, 1, 0, rw}},
+	"LPUSHX":                {{1, 1, 1, 0, rw}},
+	"LRANGE":                {{1, 1, 1, 0, ro}},
+	"LREM":                  {{1, 1, 1, 0, rw}},
+	"LSET":                  {{1, 1, 1, 0, rw}},
+	"LTRIM":                 {{1, 1, 1, 0, rw}},
+	"MEMORY USAGE":          {{1, 1, 1, 0, ro}},
+	"MGET":                  {{1, -1, 1, 0, ro}},
+	"MOVE":                  {{1, 1, 1, 0, rw}},
+	"MSET":                  {{1, -1, 2, 0, ow}},
+	"MSETNX":                {{1, -1, 2, 0, ow}},
+	"OBJECT ENCODING":       {{1, 1, 1, 0, ro}},
+	"OBJECT FREQ":           {{1, 1, 1, 0, ro}},
+	"OBJECT IDLETIME":       {{1, 1, 1, 0, ro}},
+	"OBJECT REFCOUNT":       {{1, 1, 1, 0, ro}},
+	"PERSIST":               {{1, 1, 1, 0, rw}},
+	"PEXPIRE":               {{1, 1, 1, 0, rw}},
+	"PEXPIREAT":             {{1, 1, 1, 0, rw}},
+	"PEXPIRETIME":           {{1, 1, 1, 0, ro}},
+	"PFADD":                 {{1, 1, 1, 0, rw}},
+	"PFCOUNT":               {{1, -1, 1, 0, rw}},
+	"PFMERGE":               {{1, 1, 1, 0, rw}, {2, -1, 1, 0, ro}},
+	"PSETEX":                {{1, 1, 1, 0, ow}},
+	"PTTL":                  {{1, 1, 1, 0, ro}},
+	"RENAME":                {{1, 1, 1, 0, rw | rm}, {2, 2, 1, 0, ow}},
+	"RENAMENX":              {{1, 1, 1, 0, rw | rm}, {2, 2, 1, 0, ow}},
+	"RESTORE":               {{1, 1, 1, 0, ow}},
+	"RPOP":                  {{1, 1, 1, 0, rw}},
+	"RPOPLPUSH":             {{1, 2, 1, 0, rw}},
+	"RPUSH":                 {{1, 1, 1, 0, rw}},
+	"RPUSHX":                {{1, 1, 1, 0, rw}},
+	"SADD":                  {{1, 1, 1, 0, rw}},
+	"SCARD":                 {{1, 1, 1, 0, ro}},
+	"SDIFF":                 {{1, -1, 1, 0, ro}},
+	"SDIFFSTORE":            {{1, 1, 1, 0, ow}, {2, -1, 1, 0, ro}},
+	"SET":                   {{1, 1, 1, 0, rw}},
+	"SETBIT":                {{1, 1, 1, 0, rw}},
+	"SETEX":                 {{1, 1, 1, 0, ow}},
+	"SETNX":                 {{1, 1, 1, 0, ow}},
+	"SETRANGE":              {{1, 1, 1, 0, rw}},
+	"SINTER":                {{1, -1, 1, 0, ro}},
+	"SINTERCARD":            {{0, 0, 0, 1, ro}},
+	"SINTERSTORE":           {{1, 1, 1, 0, ow}, {2, -1, 1, 0, ro}},
+	"SISMEMBER":             {{1, 1, 1, 0, ro}},
+	"SMEMBERS":              {{1, 1, 1, 0, ro}},
+	"SMISMEMBER":            {{1, 1, 1, 0, ro}},
+	"SMOVE":                 {{1, 2, 1, 0, rw}},
+	"SORT":                  {{1, 1, 1, 0, ro}},
+	"SORT_RO":               {{1, 1, 1, 0, ro}},
+	"SPOP":                  {{1, 1, 1, 0, rw}},
+	"SRANDMEMBER":           {{1, 1, 1, 0, ro}},
+	"SREM":                  {{1, 1, 1, 0, rw}},
+	"SSCAN":                 {{1, 1, 1, 0, ro}},
+	"STRLEN":                {{1, 1, 1, 0, ro}},
+	"SUNION":                {{1, -1, 1, 0, ro}},
+	"SUNIONSTORE":           {{1, 1, 1, 0, ow}, {2, -1, 1, 0, ro}},
+	"TOUCH":                 {{1, -1, 1, 0, ro}},
+	"TTL":                   {{1, 1, 1, 0, ro}},
+	"TYPE":                  {{1, 1, 1, 0, ro}},
+	"UNLINK":                {{1, -1, 1, 0, rm}},
+	"WATCH":                 {{1, -1, 1, 0, ro}},
+	"XACK":                  {{1, 1, 1, 0, rw}},
+	"XADD":                  {{1, 1, 1, 0, rw}},
+	"XAUTOCLAIM":            {{1, 1, 1, 0, rw}},
+	"XCLAIM":                {{1, 1, 1, 0, rw}},
+	"XDEL":                  {{1, 1, 1, 0, rw}},
+	"XGROUP CREATE":         {{1, 1, 1, 0, rw}},
+	"XGROUP CREATECONSUMER": {{1, 1, 1, 0, rw}},
+	"XGROUP DELCONSUMER":    {{1, 1, 1, 0, rw}},
+	"XGROUP DESTROY":        {{1, 1, 1, 0, rw}},
+	"XGROUP SETID":          {{1, 1, 1, 0, rw}},
+	"XINFO CONSUMERS":       {{1, 1, 1, 0, ro}},
+	"XINFO GROUPS":          {{1, 1, 1, 0, ro}},
+	"XINFO STREAM":          {{1, 1, 1, 0, ro}},
+	"XLEN":                  {{1, 1, 1, 0, ro}},
+	"XPENDING":              {{1, 1, 1, 0, ro}},
+	"XRANGE":                {{1, 1, 1, 0, ro}},
+	"XREVRANGE":             {{1, 1, 1, 0, ro}},
+	"XSETID":                {{1, 1, 1, 0, rw}},
+	"XTRIM":                 {{1, 1, 1, 0, rw}},
+	"ZADD":                  {{1, 1, 1, 0, rw}},
+	"ZCARD":                 {{1, 1, 1, 0, ro}},
+	"ZCOUNT":                {{1, 1, 1, 0, ro}},
+	"ZDIFF":                 {{0, 0, 0, 1, ro}},
+	"ZDIFFSTORE":            {{1, 1, 1, 0, ow}, {0, 0, 0, 2, ro}},
+	"ZINCRBY":               {{1, 1, 1, 0, rw}},
+	"ZINTER":                {{0, 0, 0, 1, ro}},
+	"ZINTERCARD":            {{0, 0, 0, 1, ro}},
+	"ZINTERSTORE":           {{1, 1, 1, 0, ow}, {0, 0, 0, 2, ro}},
+	"ZLEXCOUNT":             {{1, 1, 1, 0, ro}},
+	"ZMPOP":                 {{0, 0, 0, 1, rw}},
+	"ZMSCORE":               {{1, 1, 1, 0, ro}},
+	"ZPOPMAX":               {{1, 1, 1, 0, rw}},
+	"ZPOPMIN":               {{1, 1, 1, 0, rw}},
+	"ZRANDMEMBER":           {{1, 1, 1, 0, ro}},
+	"ZRANGE":                {{1, 1, 1, 0, ro}},
+	"ZRANGEBYLEX":           {{1, 1, 1, 0, ro}},
+	"ZRANGEBYSCORE":         {{1, 1, 1, 0, ro}},
+	"ZRANGESTORE":           {{1, 1, 1, 0, ow}, {2, 2, 1, 0, ro}},
+	"ZRANK":                 {{1, 1, 1, 0, ro}},
+	"ZREM":                  {{1, 1, 1, 0, rw}},
+	"ZREMRANGEBYLEX":        {{1, 1, 1, 0, rw}},
+	"ZREMRANGEBYRANK":       {{1, 1, 1, 0, rw}},
+	"ZREMRANGEBYSCORE":      {{1, 1, 1, 0, rw}},
+	"ZREVRANGE":             {{1, 1, 1, 0, ro}},
+	"ZREVRANGEBYLEX":        {{1, 1, 1, 0, ro}},
+	"ZREVRANGEBYSCORE":      {{1, 1, 1, 0, ro}},
+	"ZREVRANK":              {{1, 1, 1, 0, ro}},
+	"ZSCAN":                 {{1, 1, 1, 0, ro}},
+	"ZSCORE":                {{1, 1, 1, 0, ro}},
+	"ZUNION":                {{0, 0, 0, 1, ro}},
+	"ZUNIONSTORE":           {{1, 1, 1, 0, ow}, {0, 0, 0, 2, ro}},
+}
+
+// commandKey is the index of a key in the arguments (starting from 0) with how the command uses it.
+type commandKey struct {
+	index int
+	flags keyFlags
+}
+
+// commandKeys returns the keys of the command in the arguments with the flags of their key spec.
+func commandKeys(command string, arguments []string) []commandKey {
+	var keys []commandKey
+	for _, spec := range keySpecs[command] {
+		if spec.first > 0 {
+			last := spec.last
+			if last < 0 {
+				last = len(arguments) + last + 1
+			}
+
+			for i := spec.first; i <= last && i <= len(arguments); i += spec.step {
+				keys = append(keys, commandKey{i - 1, spec.flags})
+			}
+		}
+
+		if spec.numKeys > 0 && spec.numKeys <= len(arguments) {
+			numKeys, err := strconv.Atoi(arguments[spec.numKeys-1])
+			if err != nil {
+				continue
+			}
+
+			for i := spec.numKeys; i < spec.numKeys+numKeys && i < len(arguments); i++ {
+				keys = append(keys, commandKey{i, spec.flags})
+			}
+		}
+	}
+
+	// a positional key is never a keyword, even when it is named like one (e.g. "SORT store STORE dest")
+	positional := map[int]bool{}
+	for _, k := range keys {
+		positional[k.index] = true
+	}
+
+	for _, spec := range keywordKeySpecs[command] {
+		for i := 0; i < len(arguments)-1; i++ {
+			if positional[i] || !strings.EqualFold(arguments[i], spec.keyword) {
+				continue
+			}
+
+			count := 1
+			if spec.half {
+				count = (len(arguments) - i - 1) / 2
+			}
+
+			for j := i + 1; j <= i+count; j++ {
+				keys = append(keys, commandKey{j, spec.flags})
+			}
+
+			break
+		}
+	}
+
+	return keys
+}
+
+// GetKeyIndexes returns the indexes (starting from 0) of the arguments that are keys for the given command.
+func GetKeyIndexes(command string, arguments []string) []int {
+	var indexes []int
+	for _, k := range commandKeys(command, arguments) {
+		indexes = append(indexes, k.index)
+	}
+
+	return indexes
+}
+
+// IsKey reports whether the argument in the given index (starting from 0) is a key for the given command.
+func IsKey(command string, arguments []string, index int) bool {
+	for _, i := range GetKeyIndexes(command, arguments) {
+		if i == index {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Access is how a command uses a key.
+type Access int
+
+const (
+	// UnknownAccess is used for keys of scripts that can either read or write them.
+	UnknownAccess Access = iota
+	ReadAccess
+	WriteAccess
+)
+
+// KeyAccess returns how the command uses the key in the given index (starting from 0) from the flags of its key spec.
+func KeyAccess(command string, arguments []string, index int) Access {
+	// scripts and functions are flagged as modifying their keys but may only read them
+	switch command {
+	case "EVAL", "EVALSHA", "FCALL":
+		return UnknownAccess
+	}
+
+	for _, k := range commandKeys(command, arguments) {
+		if k.index == index && k.flags&(rw|ow|rm) != 0 {
+			return WriteAccess
+		}
+	}
+
+	return ReadAccess
+}
@@ -0,0 +1,75 @@
+package completer
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestKeyAccess(t *testing.T) {
+	tests := []struct {
+		Name           string
+		Command        string
+		Arguments      []string
+		Index          int
+		ExpectedAccess Access
+	}{
+		{"Read command", "GET", []string{"a"}, 0, ReadAccess},
+		{"Write command", "SET", []string{"a", "1"}, 0, WriteAccess},
+		{"Destination key", "SUNIONSTORE", []string{"dest", "a", "b"}, 0, WriteAccess},
+		{"Source key", "SUNIONSTORE", []string{"dest", "a", "b"}, 2, ReadAccess},
+		{"Destination after numkeys", "ZUNIONSTORE", []string{"dest", "2", "a", "b"}, 0, WriteAccess},
+		{"Copy destination", "COPY", []string{"a", "b"}, 1, WriteAccess},
+		{"Script key", "EVAL", []string{"return 1", "1", "a"}, 2, UnknownAccess},
+		{"Read only script key", "EVAL_RO", []string{"return 1", "1", "a"}, 2, ReadAccess},
+		{"Function key", "FCALL", []string{"myfunc", "1", "a"}, 2, UnknownAccess},
+		{"Popped key after numkeys", "LMPOP", []string{"2", "a", "b", "LEFT"}, 2, WriteAccess},
+		{"Subcommand key", "XINFO STREAM", []string{"events"}, 0, ReadAccess},
+		{"Subcommand write key", "XGROUP CREATE", []string{"events", "group", "$"}, 0, WriteAccess},
+		{"Sorted key", "SORT", []string{"list", "STORE", "dest"}, 0, ReadAccess},
+		{"Sort destination", "SORT", []string{"list", "LIMIT", "0", "10", "store", "dest"}, 5, WriteAccess},
+		{"Read only sort", "SORT_RO", []string{"list"}, 0, ReadAccess},
+		{"Read only bitfield", "BITFIELD_RO", []string{"bits", "GET", "u4", "0"}, 0, ReadAccess},
+		{"Georadius destination", "GEORADIUS", []string{"geo", "15", "37", "200", "km", "STOREDIST", "dest"}, 6, WriteAccess},
+		{"Renamed key", "RENAME", []string{"a", "b"}, 0, WriteAccess},
+		{"Bitop source", "BITOP", []string{"AND", "dest", "a", "b"}, 2, ReadAccess},
+		{"Bitop destination", "BITOP", []string{"AND", "dest", "a", "b"}, 1, WriteAccess},
+		{"Deleted key", "UNLINK", []string{"a"}, 0, WriteAccess},
+		{"Stream", "XREAD", []string{"COUNT", "10", "STREAMS", "a", "b", "0", "0"}, 4, ReadAccess},
+		{"Group stream", "XREADGROUP", []string{"GROUP", "g", "c", "STREAMS", "a", ">"}, 4, ReadAccess},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			access := KeyAccess(test.Command, test.Arguments, test.Index)
+			if access != test.ExpectedAccess {
+				t.Errorf("expected access %v, got %v", test.ExpectedAccess, access)
+			}
+		})
+	}
+}
+
+func TestGetKeyIndexes(t *testing.T) {
+	tests := []struct {
+		Name            string
+		Command         string
+		Arguments       []string
+		ExpectedIndexes []int
+	}{
+		{"Single key", "GET", []string{"a"}, []int{0}},
+		{"Keys after numkeys", "ZUNIONSTORE", []string{"dest", "2", "a", "b"}, []int{0, 2, 3}},
+		{"Sort destination", "SORT", []string{"list", "STORE", "dest"}, []int{0, 2}},
+		{"Key named like the keyword", "SORT", []string{"store", "STORE", "dest"}, []int{0, 2}},
+		{"Streams", "XREAD", []string{"COUNT", "10", "STREAMS", "a", "b", "0", "0"}, []int{3, 4}},
+		{"Streams without options", "XREAD", []string{"STREAMS", "a", "$"}, []int{1}},
+		{"Group streams", "XREADGROUP", []string{"GROUP", "g", "c", "BLOCK", "0", "STREAMS", "a", "b", ">", ">"}, []int{6, 7}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			indexes := GetKeyIndexes(test.Command, test.Arguments)
+			if fmt.Sprint(indexes) != fmt.Sprint(test.ExpectedIndexes) {
+				t.Errorf("%v - Unexpected indexes: %v (expected %v)", test.Name, indexes, test.ExpectedIndexes)
+			}
+		})
+	}
+}
@@ -0,0 +1,331 @@
+package completer
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fagnercarvalho/redis-lsp/ast"
+	"github.com/fagnercarvalho/redis-lsp/lua"
+	"github.com/fagnercarvalho/redis-lsp/token"
+)
+
+// scriptFunctions are the functions and constants of the redis object available to Lua scripts.
+var scriptFunctions = []Item{
+	{Label: "call", Kind: FunctionItem, Detail: "redis.call(command [, arg ...]) runs a command, errors are raised"},
+	{Label: "pcall", Kind: FunctionItem, Detail: "redis.pcall(command [, arg ...]) runs a command, errors are returned as a table"},
+	{Label: "log", Kind: FunctionItem, Detail: "redis.log(level, message) writes a message to the Redis log"},
+	{Label: "sha1hex", Kind: FunctionItem, Detail: "redis.sha1hex(value) returns the SHA1 hex digest of the value"},
+	{Label: "error_reply", Kind: FunctionItem, Detail: "redis.error_reply(message) returns an error reply"},
+	{Label: "status_reply", Kind: FunctionItem, Detail: "redis.status_reply(message) returns a status reply"},
+	{Label: "LOG_DEBUG", Kind: ConstantItem, Detail: "log level"},
+	{Label: "LOG_VERBOSE", Kind: ConstantItem, Detail: "log level"},
+	{Label: "LOG_NOTICE", Kind: ConstantItem, Detail: "log level"},
+	{Label: "LOG_WARNING", Kind: ConstantItem, Detail: "log level"},
+}
+
+// libraryFunctions are the functions of the redis object that are only available to function libraries.
+var libraryFunctions = []Item{
+	{Label: "register_function", Kind: FunctionItem, Detail: "redis.register_function(name, callback) registers a function of the library"},
+}
+
+// scriptCompletion returns the items when the position is inside the Lua script of an EVAL, EVAL_RO or SCRIPT LOAD statement,
+// ok is false when it is not.
+func (c Completer) scriptCompletion(statements []ast.TokenList, line int, position int) ([]Item, string, bool) {
+	statement, selected := ast.GetSelectedToken(statements, line, position)
+	if selected == nil {
+		return nil, "", false
+	}
+
+	script := ast.GetScript(statement)
+	if script == nil || script.Start() != selected.Start() {
+		return nil, "", false
+	}
+
+	value := script.String()
+	if value[0] != '"' && value[0] != '\'' {
+		return nil, "", false
+	}
+
+	// the position is before the opening quote or after the closing quote
+	offset := ast.PositionOffset(script, line, position)
+	if offset < 1 || (script.Type() == token.String && offset >= len(value)) {
+		return nil, "", false
+	}
+
+	source, _, err := token.UnquoteOffsets(value[:offset])
+	if err == token.ErrClosingQuote {
+		return nil, "", false
+	}
+
+	items, typed := c.luaItems(statementGlobals(statement), scriptFunctions, source)
+
+	return items, typed, true
+}
+
+// CompleteScript returns the completion items for a position in a Lua script file
+// and the text before the position that should be replaced by the item.
+// The KEYS and ARGV declared in the header of the script are completed,
+// function libraries only have the redis object since the keys and arguments are passed to each function.
+func (c Completer) CompleteScript(text string, line int, position int) ([]Item, string) {
+	offset := 0
+	for i := 0; i < line; i++ {
+		newline := strings.IndexByte(text[offset:], '\n')
+		if newline == -1 {
+			return nil, ""
+		}
+
+		offset += newline + 1
+	}
+
+	offset += position
+	if offset > len(text) {
+		offset = len(text)
+	}
+
+	if _, ok := lua.ParseLibrary(text); ok {
+		return c.luaItems(scriptGlobals(nil, nil, true), append(libraryFunctions, scriptFunctions...), text[:offset])
+	}
+
+	header := lua.ParseHeader(text)
+
+	return c.luaItems(scriptGlobals(header.Keys, header.Arguments, header.Declared), scriptFunctions, text[:offset])
+}
+
+// luaItems returns the items for the end of the script (e.g. the redis functions after "redis.").
+func (c Completer) luaItems(globals []Item, functions []Item, source string) ([]Item, string) {
+	tokens, err := lua.Tokenize(source)
+	if len(tokens) == 0 {
+		return globals, ""
+	}
+
+	last := tokens[len(tokens)-1]
+	if last.End != len(source) {
+		// something that is not a token was typed (e.g. an unfinished string in a previous line)
+		if err != nil {
+			return nil, ""
+		}
+
+		return globals, ""
+	}
+
+	previous := func(i int) (lua.Token, bool) {
+		if len(tokens) <= i {
+			return lua.Token{}, false
+		}
+
+		return tokens[len(tokens)-1-i], true
+	}
+
+	switch last.Type {
+	case lua.String:
+		// an unfinished quoted string can be a redis.call argument
+		if err == nil || strings.HasPrefix(last.Value, "[") {
+			return nil, ""
+		}
+
+		return c.callItems(tokens)
+	case lua.Name:
+		if dot, ok := previous(1); ok && dot.Type == lua.Symbol && (dot.Value == "." || dot.Value == ":") {
+			if redis, ok := previous(2); ok && dot.Value == "." && redis.Value == "redis" {
+				return c.filterItems(functions, last.Value), last.Value
+			}
+
+			return nil, ""
+		}
+
+		return c.filterItems(globals, last.Value), last.Value
+	case lua.Symbol:
+		if last.Value == "." {
+			if redis, ok := previous(1); ok && redis.Value == "redis" {
+				return functions, ""
+			}
+
+			return nil, ""
+		}
+
+		return globals, ""
+	}
+
+	// there is nothing to complete in comments, numbers and keywords
+	return nil, ""
+}
+
+// statementGlobals returns the globals of the script of an EVAL statement with the keys and arguments passed to it.
+func statementGlobals(statement ast.TokenList) []Item {
+	keys, arguments, ok := ast.GetScriptArguments(statement)
+
+	var keyValues, argumentValues []string
+	for _, k := range keys {
+		keyValues = append(keyValues, k.String())
+	}
+
+	for _, a := range arguments {
+		argumentValues = append(argumentValues, a.String())
+	}
+
+	return scriptGlobals(keyValues, argumentValues, ok)
+}
+
+// scriptGlobals returns the redis object and the KEYS and ARGV passed to the script,
+// the first key and argument are returned when it is not known what is passed (e.g. SCRIPT LOAD).
+func scriptGlobals(keys []string, arguments []string, known bool) []Item {
+	items := []Item{{Label: "redis", Kind: VariableItem, Detail: "Redis API"}}
+
+	if !known {
+		return append(items,
+			Item{Label: "KEYS[1]", Kind: VariableItem, Detail: "first key passed to the script"},
+			Item{Label: "ARGV[1]", Kind: VariableItem, Detail: "first argument passed to the script"},
+		)
+	}
+
+	for i, k := range keys {
+		items = append(items, Item{Label: fmt.Sprintf("KEYS[%v]", i+1), Kind: VariableItem, Detail: fmt.Sprintf("key %v", k)})
+	}
+
+	for i, a := range arguments {
+		items = append(items, Item{Label: fmt.Sprintf("ARGV[%v]", i+1), Kind: VariableItem, Detail: fmt.Sprintf("argument %v", a)})
+	}
+
+	return items
+}
+
+// callItems returns the items for the unfinished string at the end of the tokens when it is an argument of redis.call or redis.pcall,
+// using the same commands and options as statements (e.g. "GET" in redis.call('GE or "EX" in redis.call('SET', KEYS[1], ARGV[1], 'E).
+func (c Completer) callItems(tokens []lua.Token) ([]Item, string) {
+	typed := tokens[len(tokens)-1].Value[1:]
+	arguments, ok := callArguments(tokens[:len(tokens)-1])
+	if !ok {
+		return nil, ""
+	}
+
+	// the command and its subcommand are separate arguments (e.g. redis.call('CONFIG', 'GET', 'maxmemory'))
+	if len(arguments) <= 1 {
+		items := c.commandWordItems(arguments, typed)
+		if len(arguments) == 0 || len(items) > 0 {
+			return items, typed
+		}
+	}
+
+	command := strings.ToUpper(arguments[0])
+	arguments = arguments[1:]
+	if len(arguments) > 0 {
+		if _, ok := getCommand(command + " " + strings.ToUpper(arguments[0])); ok {
+			command += " " + strings.ToUpper(arguments[0])
+			arguments = arguments[1:]
+		}
+	}
+
+	arguments = append(arguments, typed)
+
+	return c.filterItems(c.argumentItems(command, arguments, len(arguments)-1), typed), typed
+}
+
+// commandWordItems returns the items for the word after the previous words of the command names.
+func (c Completer) commandWordItems(previous []string, typed string) []Item {
+	prefix := strings.Join(append(previous, typed), " ")
+
+	seen := map[string]bool{}
+	var items []Item
+	for _, command := range c.available(getCommands(prefix)) {
+		words := strings.Fields(command.Name)
+		if len(words) <= len(previous) || seen[words[len(previous)]] {
+			continue
+		}
+
+		word := words[len(previous)]
+		seen[word] = true
+
+		detail := fmt.Sprintf("%v subcommands", word)
+		if len(words) == len(previous)+1 {
+			detail = fmt.Sprintf("%v (since %v)", command.Signature(), command.Since)
+		}
+
+		items = append(items, Item{Label: c.applyCase(word, typed), Kind: CommandItem, Detail: detail})
+	}
+
+	return items
+}
+
+// callArguments returns the arguments of the redis.call or redis.pcall whose argument list ends at the tokens
+// when a new argument is starting, arguments that are not strings (e.g. KEYS[1]) are returned as empty strings.
+func callArguments(tokens []lua.Token) ([]string, bool) {
+	var code []lua.Token
+	for _, t := range tokens {
+		if t.Type != lua.Comment {
+			code = append(code, t)
+		}
+	}
+
+	if len(code) == 0 {
+		return nil, false
+	}
+
+	if last := code[len(code)-1]; last.Type != lua.Symbol || (last.Value != "," && last.Value != "(") {
+		return nil, false
+	}
+
+	var arguments []string
+	var argument []lua.Token
+	depth := 0
+	for i := len(code) - 1; i >= 0; i-- {
+		t := code[i]
+		if t.Type == lua.Symbol {
+			switch t.Value {
+			case ")", "]", "}":
+				depth++
+			case "(", "[", "{":
+				if depth == 0 {
+					if t.Value != "(" || !isCall(code[:i]) {
+						return nil, false
+					}
+
+					if i != len(code)-1 {
+						arguments = append(arguments, argumentValue(argument))
+					}
+
+					// the arguments were added from the last to the first
+					for l, r := 0, len(arguments)-1; l < r; l, r = l+1, r-1 {
+						arguments[l], arguments[r] = arguments[r], arguments[l]
+					}
+
+					return arguments, true
+				}
+
+				depth--
+			case ",":
+				if depth == 0 {
+					if i != len(code)-1 {
+						arguments = append(arguments, argumentValue(argument))
+					}
+
+					argument = nil
+					continue
+				}
+			}
+		}
+
+		argument = append(argument, t)
+	}
+
+	return nil, false
+}
+
+// isCall reports whether the tokens end with redis.call or redis.pcall.
+func isCall(tokens []lua.Token) bool {
+	if len(tokens) < 3 {
+		return false
+	}
+
+	name, dot, redis := tokens[len(tokens)-1], tokens[len(tokens)-2], tokens[len(tokens)-3]
+
+	return (name.Value == "call" || name.Value == "pcall") && dot.Value == "." && redis.Value == "redis"
+}
+
+// argumentValue returns the value of an argument that is a string or an empty string.
+func argumentValue(tokens []lua.Token) string {
+	if len(tokens) != 1 || tokens[0].Type != lua.String {
+		return ""
+	}
+
+	return lua.StringValue(tokens[0])
+}
@@ -0,0 +1,106 @@
+package completer
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestScriptCompletion(t *testing.T) {
+	tests := []struct {
+		Name           string
+		Text           string
+		Position       int
+		ExpectedLabels []string
+		ExpectedTyped  string
+	}{
+		{"Redis functions", `EVAL "return redis.`, -1, []string{"call", "pcall", "log", "sha1hex", "error_reply", "status_reply", "LOG_DEBUG", "LOG_VERBOSE", "LOG_NOTICE", "LOG_WARNING"}, ""},
+		{"Typed Redis function", `EVAL "return redis.pc" 0`, 21, []string{"pcall"}, "pc"},
+		{"Keys and arguments", `EVAL "return K" 2 a b c`, 14, []string{"KEYS[1]", "KEYS[2]"}, "K"},
+		{"Globals", `EVAL 'return ' 1 a b`, 13, []string{"redis", "KEYS[1]", "ARGV[1]"}, ""},
+		{"Globals without numkeys", `SCRIPT LOAD "return `, -1, []string{"redis", "KEYS[1]", "ARGV[1]"}, ""},
+		{"Command", `EVAL "return redis.call('HGETA`, -1, []string{"HGETALL"}, "HGETA"},
+		{"Lower case command", `EVAL "return redis.call('hgeta`, -1, []string{"hgetall"}, "hgeta"},
+		{"Container command", `EVAL "return redis.call('CONF`, -1, []string{"CONFIG"}, "CONF"},
+		{"Subcommand", `EVAL "return redis.call('CONFIG', 'RES`, -1, []string{"RESETSTAT"}, "RES"},
+		{"Option", `EVAL "return redis.pcall('SET', KEYS[1], ARGV[1], 'KEEP`, -1, []string{"KEEPTTL"}, "KEEP"},
+		{"Multiple lines", "EVAL \"local v = 1\nreturn redis.ca\" 0", 15, []string{"call"}, "ca"},
+		{"Escaped quotes", `EVAL "return redis.call(\"HGETA`, -1, []string{"HGETALL"}, "HGETA"},
+		{"Not a call argument", `EVAL "return string.len('HGET`, -1, nil, ""},
+		{"Other object", `EVAL "return string.`, -1, nil, ""},
+		{"Comment", `EVAL "-- red`, -1, nil, ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			// the position is in the last line, -1 is the end of the line
+			lines := strings.Split(test.Text, "\n")
+			line := len(lines) - 1
+			position := test.Position
+			if position == -1 {
+				position = len(lines[line])
+			}
+
+			items, typed := Completer{Case: AsTyped}.Complete(test.Text, line, position)
+
+			var labels []string
+			for _, item := range items {
+				labels = append(labels, item.Label)
+			}
+
+			if len(labels) != len(test.ExpectedLabels) {
+				t.Fatalf("%v - Unexpected labels: %v (expected %v)", test.Name, labels, test.ExpectedLabels)
+			}
+
+			for i := range labels {
+				if labels[i] != test.ExpectedLabels[i] {
+					t.Errorf("%v - Unexpected label %v: %v (expected %v)", test.Name, i, labels[i], test.ExpectedLabels[i])
+				}
+			}
+
+			if typed != test.ExpectedTyped {
+				t.Errorf("%v - Unexpected typed text: %q (expected %q)", test.Name, typed, test.ExpectedTyped)
+			}
+		})
+	}
+}
+
+func TestCompleteScript(t *testing.T) {
+	text := "-- KEYS: counter\n-- ARGV: 10\nlocal n = redis.call('INCRBY', K"
+
+	items, typed := Completer{}.CompleteScript(text, 2, 32)
+
+	var labels []string
+	for _, item := range items {
+		labels = append(labels, item.Label)
+	}
+
+	if strings.Join(labels, ",") != "KEYS[1]" || typed != "K" {
+		t.Errorf("Unexpected labels %v and typed text %q", labels, typed)
+	}
+
+	items, typed = Completer{}.CompleteScript("return redis.s", 0, 14)
+	if len(items) != 2 || items[0].Label != "sha1hex" || items[1].Label != "status_reply" || typed != "s" {
+		t.Errorf("Unexpected items %+v and typed text %q", items, typed)
+	}
+}
+
+func TestFunctionCompletion(t *testing.T) {
+	c := Completer{Functions: func() map[string]string {
+		return map[string]string{"my_hset": "mylib", "my_hget": "mylib", "knockknock": "jokes"}
+	}}
+
+	items, typed := c.Complete("FCALL my_h", 0, 10)
+	if len(items) != 2 || items[0].Label != "my_hget" || items[1].Label != "my_hset" || items[0].Detail != "function of library mylib" || typed != "my_h" {
+		t.Errorf("Unexpected items %+v and typed text %q", items, typed)
+	}
+
+	items, _ = c.CompleteScript("#!lua name=mylib\nredis.register_function('f', function(keys, args) return ", 1, 62)
+	if len(items) != 1 || items[0].Label != "redis" {
+		t.Errorf("Unexpected library globals %+v", items)
+	}
+
+	items, _ = c.CompleteScript("#!lua name=mylib\nredis.reg", 1, 9)
+	if len(items) != 1 || items[0].Label != "register_function" {
+		t.Errorf("Unexpected library functions %+v", items)
+	}
+}
@@ -0,0 +1,373 @@
+package completer
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Argument is a node of the syntax tree of a command.
+// It is either a single word (a value such as "key" or a literal token such as "NX")
+// or a group with one or more alternative sequences of arguments.
+type Argument struct {
+	Name     string
+	Token    bool
+	Optional bool
+	Multiple bool
+	Choices  [][]*Argument
+}
+
+// IsGroup reports whether the argument is a group of arguments instead of a single word.
+func (a *Argument) IsGroup() bool {
+	return len(a.Choices) > 0
+}
+
+// ParseArguments parses the arguments of a command in the redis-cli help syntax.
+func ParseArguments(syntax string) []*Argument {
+	words := splitSyntax(syntax)
+	arguments, _ := parseSequence(words, 0)
+
+	return arguments
+}
+
+// splitSyntax splits the syntax in words, brackets, parentheses and pipes.
+func splitSyntax(syntax string) []string {
+	var words []string
+	var word strings.Builder
+	flush := func() {
+		if word.Len() > 0 {
+			words = append(words, word.String())
+			word.Reset()
+		}
+	}
+
+	for _, r := range syntax {
+		switch {
+		case r == '[' || r == ']' || r == '(' || r == ')' || r == '|':
+			flush()
+			words = append(words, string(r))
+		case unicode.IsSpace(r):
+			flush()
+		default:
+			word.WriteRune(r)
+		}
+	}
+
+	flush()
+
+	return words
+}
+
+// parseSequence parses words until the end of the current group and returns the index where it stopped.
+func parseSequence(words []string, index int) ([]*Argument, int) {
+	var sequence []*Argument
+	for index < len(words) {
+		word := words[index]
+		switch word {
+		case "]", ")", "|":
+			return sequence, index
+		case "[", "(":
+			group, next := parseGroup(words, index+1, word == "[")
+			sequence = append(sequence, group)
+			index = next
+			continue
+		case "...":
+			if len(sequence) > 0 {
+				sequence[len(sequence)-1].Multiple = true
+			}
+		default:
+			sequence = append(sequence, &Argument{Name: word, Token: isToken(word)})
+		}
+
+		index++
+	}
+
+	return sequence, index
+}
+
+func parseGroup(words []string, index int, optional bool) (*Argument, int) {
+	group := &Argument{Optional: optional}
+	for index < len(words) {
+		sequence, next := parseSequence(words, index)
+		group.Choices = append(group.Choices, sequence)
+		index = next
+
+		if index >= len(words) || words[index] != "|" {
+			break
+		}
+
+		index++
+	}
+
+	// "[key ...]" and "[key value ...]" mean the whole group can be repeated
+	for _, choice := range group.Choices {
+		if len(choice) == 0 {
+			continue
+		}
+
+		last := choice[len(choice)-1]
+		if last.Multiple && !last.IsGroup() {
+			last.Multiple = false
+			group.Multiple = true
+		}
+	}
+
+	return group, index + 1
+}
+
+// isToken reports whether the word is a literal token (e.g. NX) instead of a value (e.g. key).
+func isToken(word string) bool {
+	for _, r := range word {
+		if unicode.IsLower(r) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Options returns every literal token that can be used as an argument.
+func Options(arguments []*Argument) []string {
+	var options []string
+	seen := map[string]bool{}
+	var walk func([]*Argument)
+	walk = func(arguments []*Argument) {
+		for _, a := range arguments {
+			if a.IsGroup() {
+				for _, choice := range a.Choices {
+					walk(choice)
+				}
+
+				continue
+			}
+
+			if a.Token && !seen[a.Name] && strings.IndexFunc(a.Name, unicode.IsLetter) != -1 {
+				seen[a.Name] = true
+				options = append(options, a.Name)
+			}
+		}
+	}
+
+	walk(arguments)
+
+	return options
+}
+
+// maxMatchSteps limits the backtracking of Match for syntaxes with many optional and repeated arguments.
+const maxMatchSteps = 10000
+
+// Match returns the syntax argument matched by each value (e.g. the "EX" token or the "seconds" value)
+// or nil when the values do not follow the syntax.
+func Match(arguments []*Argument, values []string) []*Argument {
+	return MatchAccepted(arguments, values, nil)
+}
+
+// MatchAccepted is Match where a value argument only matches the values it accepts (e.g. integers for "seconds"),
+// so options are not taken as values of a repeated argument (e.g. JUSTID in "XCLAIM key group consumer 0 1-1 JUSTID").
+func MatchAccepted(arguments []*Argument, values []string, accepts func(a *Argument, value string) bool) []*Argument {
+	m := &matcher{values: values, matched: make([]*Argument, len(values)), accepts: accepts}
+	ok := m.sequence(arguments, 0, func(position int) bool {
+		return position == len(values)
+	})
+
+	if !ok {
+		return nil
+	}
+
+	return m.matched
+}
+
+// Mismatch is where values stop following a syntax: Index is the first value that does not follow it,
+// which is the number of values when more are required, and Expected are the arguments that could be there.
+type Mismatch struct {
+	Index    int
+	Expected []*Argument
+}
+
+// FindMismatch returns where the values stop following the syntax, ok is false when they follow it
+// or the syntax has too many alternatives to know.
+// When accepts is not nil a value argument only matches the values it accepts (e.g. integers for "seconds").
+func FindMismatch(arguments []*Argument, values []string, accepts func(a *Argument, value string) bool) (Mismatch, bool) {
+	m := &matcher{values: values, matched: make([]*Argument, len(values)), accepts: accepts}
+	ok := m.sequence(arguments, 0, func(position int) bool {
+		return position == len(values)
+	})
+
+	if ok || m.steps > maxMatchSteps {
+		return Mismatch{}, false
+	}
+
+	return Mismatch{Index: m.furthest, Expected: m.expected}, true
+}
+
+// Conflicts reports whether the arguments are alternatives of the same group (e.g. NX and XX in "[NX | XX]"
+// or count and RESET in "[count | RESET]"), names are compared ignoring case since tokens can be written in any case.
+func Conflicts(arguments []*Argument, a string, b string) bool {
+	for _, argument := range arguments {
+		first := map[string]int{}
+		for i, choice := range argument.Choices {
+			if len(choice) > 0 {
+				first[strings.ToUpper(choice[0].Name)] = i
+			}
+
+			if Conflicts(choice, a, b) {
+				return true
+			}
+		}
+
+		i, ok := first[strings.ToUpper(a)]
+		j, ok2 := first[strings.ToUpper(b)]
+		if ok && ok2 && i != j {
+			return true
+		}
+	}
+
+	return false
+}
+
+type matcher struct {
+	values  []string
+	matched []*Argument
+	steps   int
+
+	// accepts reports whether a value argument matches a value, every value matches when it is nil
+	accepts func(a *Argument, value string) bool
+
+	// furthest is the position of the furthest value reached and expected are the arguments tried there
+	furthest int
+	expected []*Argument
+}
+
+// reach records that the argument was tried at the position.
+func (m *matcher) reach(position int, a *Argument) {
+	if position > m.furthest {
+		m.furthest = position
+		m.expected = nil
+	}
+
+	if position != m.furthest || a == nil {
+		return
+	}
+
+	for _, e := range m.expected {
+		if e.Name == a.Name && e.Token == a.Token {
+			return
+		}
+	}
+
+	m.expected = append(m.expected, a)
+}
+
+// sequence matches the arguments from the position and calls next with the position after them,
+// it stops at the first match where next returns true.
+func (m *matcher) sequence(arguments []*Argument, position int, next func(int) bool) bool {
+	if len(arguments) == 0 {
+		return next(position)
+	}
+
+	return m.argument(arguments[0], position, func(p int) bool {
+		return m.sequence(arguments[1:], p, next)
+	})
+}
+
+func (m *matcher) argument(a *Argument, position int, next func(int) bool) bool {
+	if m.occurrences(a, position, next) {
+		return true
+	}
+
+	return a.Optional && next(position)
+}
+
+// occurrences matches the argument once and then again for as long as it can be repeated.
+func (m *matcher) occurrences(a *Argument, position int, next func(int) bool) bool {
+	return m.once(a, position, func(p int) bool {
+		if a.Multiple && p > position && m.occurrences(a, p, next) {
+			return true
+		}
+
+		return next(p)
+	})
+}
+
+func (m *matcher) once(a *Argument, position int, next func(int) bool) bool {
+	m.steps++
+	if m.steps > maxMatchSteps {
+		return false
+	}
+
+	if a.IsGroup() {
+		for _, choice := range a.Choices {
+			if m.sequence(choice, position, next) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	if position >= len(m.values) || (a.Token && !strings.EqualFold(m.values[position], a.Name)) ||
+		(!a.Token && m.accepts != nil && !m.accepts(a, m.values[position])) {
+		m.reach(position, a)
+		return false
+	}
+
+	m.matched[position] = a
+	m.reach(position+1, nil)
+
+	return next(position + 1)
+}
+
+// ArgumentGroup returns the first and last index of the values matched by the same occurrence
+// of a group of arguments (e.g. "EX 10" in "SET key value EX 10") containing the value at the index.
+// It returns false when the value is not part of a group with other values.
+func ArgumentGroup(arguments []*Argument, matched []*Argument, index int) (int, int, bool) {
+	if index < 0 || index >= len(matched) {
+		return 0, 0, false
+	}
+
+	type place struct {
+		sequence []*Argument
+		position int
+	}
+
+	// the sequence of each argument inside a group, the arguments of the command itself have no group
+	places := map[*Argument]place{}
+	var walk func([]*Argument)
+	walk = func(arguments []*Argument) {
+		for _, a := range arguments {
+			for _, choice := range a.Choices {
+				for i, c := range choice {
+					places[c] = place{sequence: choice, position: i}
+				}
+
+				walk(choice)
+			}
+		}
+	}
+
+	walk(arguments)
+
+	if _, ok := places[matched[index]]; !ok {
+		return 0, 0, false
+	}
+
+	sameOccurrence := func(previous *Argument, next *Argument) bool {
+		p, ok := places[previous]
+		n, ok2 := places[next]
+		return ok && ok2 && &p.sequence[0] == &n.sequence[0] && p.position < n.position
+	}
+
+	start, end := index, index
+	for start > 0 && sameOccurrence(matched[start-1], matched[start]) {
+		start--
+	}
+
+	for end < len(matched)-1 && sameOccurrence(matched[end], matched[end+1]) {
+		end++
+	}
+
+	if start == end {
+		return 0, 0, false
+	}
+
+	return start, end, true
+}
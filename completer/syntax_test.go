@@ -0,0 +1,141 @@
+package completer
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		Name          string
+		Command       string
+		Values        []string
+		ExpectedNames []string
+	}{
+		{"Required arguments", "GET", []string{"user:1"}, []string{"key"}},
+		{"Optional token", "SET", []string{"k", "v", "ex", "10"}, []string{"key", "value", "EX", "seconds"}},
+		{"Value equal to a token", "SET", []string{"ex", "ex"}, []string{"key", "value"}},
+		{"Repeated arguments", "MSET", []string{"a", "1", "b", "2"}, []string{"key", "value", "key", "value"}},
+		{"Missing argument", "GET", nil, nil},
+		{"Too many arguments", "GET", []string{"a", "b"}, nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			arguments, ok := CommandArguments(test.Command)
+			if !ok {
+				t.Fatalf("command %v not found", test.Command)
+			}
+
+			matched := Match(arguments, test.Values)
+			if len(matched) != len(test.ExpectedNames) {
+				t.Fatalf("expected %v arguments, got %v", len(test.ExpectedNames), len(matched))
+			}
+
+			for i, a := range matched {
+				if a.Name != test.ExpectedNames[i] {
+					t.Errorf("expected argument %v to be %v, got %v", i, test.ExpectedNames[i], a.Name)
+				}
+			}
+		})
+	}
+}
+
+func TestArgumentGroup(t *testing.T) {
+	tests := []struct {
+		Name          string
+		Command       string
+		Values        []string
+		Index         int
+		ExpectedStart int
+		ExpectedEnd   int
+		ExpectedOk    bool
+	}{
+		{"Option with value", "SET", []string{"k", "v", "EX", "10"}, 3, 2, 3, true},
+		{"Option without value", "SET", []string{"k", "v", "NX"}, 2, 0, 0, false},
+		{"Command argument", "SET", []string{"k", "v", "EX", "10"}, 0, 0, 0, false},
+		{"Repeated group", "MSET", []string{"a", "1", "b", "2", "c", "3"}, 4, 4, 5, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			arguments, _ := CommandArguments(test.Command)
+			start, end, ok := ArgumentGroup(arguments, Match(arguments, test.Values), test.Index)
+			if start != test.ExpectedStart || end != test.ExpectedEnd || ok != test.ExpectedOk {
+				t.Errorf("expected %v %v %v, got %v %v %v", test.ExpectedStart, test.ExpectedEnd, test.ExpectedOk, start, end, ok)
+			}
+		})
+	}
+}
+
+func TestFindMismatch(t *testing.T) {
+	tests := []struct {
+		Name             string
+		Command          string
+		Values           []string
+		ExpectedIndex    int
+		ExpectedNames    []string
+		ExpectedMismatch bool
+		Typed            bool
+	}{
+		{"Matching values", "SET", []string{"k", "v", "EX", "10"}, 0, nil, false, false},
+		{"Missing value after option", "ZRANGEBYSCORE", []string{"z", "0", "1", "LIMIT", "0"}, 5, []string{"count"}, true, false},
+		{"Missing required argument", "GET", nil, 0, []string{"key"}, true, false},
+		{"Unknown option", "SET", []string{"k", "v", "FOO"}, 2, []string{"NX", "XX", "GET", "EX", "PX", "EXAT", "PXAT", "KEEPTTL"}, true, false},
+		{"Conflicting option", "SET", []string{"k", "v", "EX", "10", "PX", "5"}, 4, nil, true, false},
+		{"Too many arguments", "GET", []string{"a", "b"}, 1, nil, true, false},
+		{"Invalid value", "XADD", []string{"s", "12x", "*", "f", "v"}, 1, []string{"NOMKSTREAM", "MAXLEN", "MINID", "*", "id"}, true, true},
+		{"Valid values", "XADD", []string{"s", "1-0", "f", "v"}, 0, nil, false, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			arguments, _ := CommandArguments(test.Command)
+			var accepts func(*Argument, string) bool
+			if test.Typed {
+				accepts = func(a *Argument, value string) bool {
+					return ValidValue(ArgumentType(test.Command, a.Name, test.Values), value)
+				}
+			}
+
+			mismatch, ok := FindMismatch(arguments, test.Values, accepts)
+			if ok != test.ExpectedMismatch || mismatch.Index != test.ExpectedIndex {
+				t.Fatalf("expected %v %v, got %v %v", test.ExpectedIndex, test.ExpectedMismatch, mismatch.Index, ok)
+			}
+
+			var names []string
+			for _, e := range mismatch.Expected {
+				names = append(names, e.Name)
+			}
+
+			if strings.Join(names, " ") != strings.Join(test.ExpectedNames, " ") {
+				t.Errorf("expected arguments %v, got %v", test.ExpectedNames, names)
+			}
+		})
+	}
+}
+
+func TestConflicts(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Command  string
+		A        string
+		B        string
+		Expected bool
+	}{
+		{"Alternative tokens", "SET", "NX", "XX", true},
+		{"Alternative options with values", "SET", "ex", "px", true},
+		{"Tokens of different groups", "SET", "NX", "GET", false},
+		{"Same token", "SET", "NX", "NX", false},
+		{"Value and token", "ACL LOG", "count", "RESET", true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			arguments, _ := CommandArguments(test.Command)
+			if actual := Conflicts(arguments, test.A, test.B); actual != test.Expected {
+				t.Errorf("expected %v, got %v", test.Expected, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,213 @@
+package completer
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ValueType is the kind of value an argument of a command accepts.
+type ValueType int
+
+const (
+	AnyValue ValueType = iota
+	IntegerValue
+	FloatValue
+	ScoreRangeValue
+	LexRangeValue
+	StreamIDValue
+)
+
+// String returns what the values of the type look like, for messages.
+func (t ValueType) String() string {
+	switch t {
+	case IntegerValue:
+		return "an integer"
+	case FloatValue:
+		return "a number"
+	case ScoreRangeValue:
+		return "a score such as 1, (1, -inf or +inf"
+	case LexRangeValue:
+		return "a range such as [a, (a, - or +"
+	case StreamIDValue:
+		return "a stream ID such as 1526919030474-0, *, $ or >"
+	}
+
+	return "a value"
+}
+
+// argumentTypes are the types of the arguments by their name in the command syntax.
+var argumentTypes = map[string]ValueType{
+	"bit":                    IntegerValue,
+	"bits":                   IntegerValue,
+	"client-id":              IntegerValue,
+	"cluster-bus-port":       IntegerValue,
+	"config-epoch":           IntegerValue,
+	"count":                  IntegerValue,
+	"cursor":                 IntegerValue,
+	"db":                     IntegerValue,
+	"decrement":              IntegerValue,
+	"destination-db":         IntegerValue,
+	"end":                    IntegerValue,
+	"end-slot":               IntegerValue,
+	"entries-added":          IntegerValue,
+	"entries-read":           IntegerValue,
+	"frequency":              IntegerValue,
+	"height":                 FloatValue,
+	"id":                     StreamIDValue,
+	"increment":              IntegerValue,
+	"index":                  IntegerValue,
+	"index1":                 IntegerValue,
+	"index2":                 IntegerValue,
+	"last-id":                StreamIDValue,
+	"lastid":                 StreamIDValue,
+	"latitude":               FloatValue,
+	"len":                    IntegerValue,
+	"limit":                  IntegerValue,
+	"longitude":              FloatValue,
+	"max-deleted-id":         StreamIDValue,
+	"milliseconds":           IntegerValue,
+	"min-idle-time":          IntegerValue,
+	"min-match-len":          IntegerValue,
+	"ms":                     IntegerValue,
+	"num-matches":            IntegerValue,
+	"numkeys":                IntegerValue,
+	"numlocal":               IntegerValue,
+	"numreplicas":            IntegerValue,
+	"offset":                 IntegerValue,
+	"port":                   IntegerValue,
+	"protover":               IntegerValue,
+	"radius":                 FloatValue,
+	"rank":                   IntegerValue,
+	"score":                  FloatValue,
+	"seconds":                IntegerValue,
+	"slot":                   IntegerValue,
+	"start":                  IntegerValue,
+	"start-slot":             IntegerValue,
+	"stop":                   IntegerValue,
+	"timeout":                FloatValue,
+	"ttl":                    IntegerValue,
+	"unix-time-milliseconds": IntegerValue,
+	"unix-time-seconds":      IntegerValue,
+	"version":                IntegerValue,
+	"weight":                 FloatValue,
+	"width":                  FloatValue,
+}
+
+// commandArgumentTypes are the types of the arguments of a command that differ from the type of their name,
+// a function chooses the type from the arguments of the statement (e.g. ZRANGE min is a score with BYSCORE).
+var commandArgumentTypes = map[string]map[string]func(values []string) ValueType{
+	"BITFIELD":         {"offset": is(AnyValue)},
+	"BITFIELD_RO":      {"offset": is(AnyValue)},
+	"CLIENT PAUSE":     {"timeout": is(IntegerValue)},
+	"HINCRBYFLOAT":     {"increment": is(FloatValue)},
+	"INCRBYFLOAT":      {"increment": is(FloatValue)},
+	"MIGRATE":          {"timeout": is(IntegerValue)},
+	"WAIT":             {"timeout": is(IntegerValue)},
+	"WAITAOF":          {"timeout": is(IntegerValue)},
+	"XADD":             {"threshold": trimThreshold},
+	"XAUTOCLAIM":       {"start": is(StreamIDValue)},
+	"XPENDING":         {"start": is(StreamIDValue), "end": is(StreamIDValue)},
+	"XRANGE":           {"start": is(StreamIDValue), "end": is(StreamIDValue)},
+	"XREVRANGE":        {"start": is(StreamIDValue), "end": is(StreamIDValue)},
+	"XTRIM":            {"threshold": trimThreshold},
+	"ZCOUNT":           {"min": is(ScoreRangeValue), "max": is(ScoreRangeValue)},
+	"ZINCRBY":          {"increment": is(FloatValue)},
+	"ZLEXCOUNT":        {"min": is(LexRangeValue), "max": is(LexRangeValue)},
+	"ZRANGE":           {"min": zrangeBound, "max": zrangeBound},
+	"ZRANGEBYLEX":      {"min": is(LexRangeValue), "max": is(LexRangeValue)},
+	"ZRANGEBYSCORE":    {"min": is(ScoreRangeValue), "max": is(ScoreRangeValue)},
+	"ZRANGESTORE":      {"min": zrangeBound, "max": zrangeBound},
+	"ZREMRANGEBYLEX":   {"min": is(LexRangeValue), "max": is(LexRangeValue)},
+	"ZREMRANGEBYSCORE": {"min": is(ScoreRangeValue), "max": is(ScoreRangeValue)},
+	"ZREVRANGEBYLEX":   {"min": is(LexRangeValue), "max": is(LexRangeValue)},
+	"ZREVRANGEBYSCORE": {"min": is(ScoreRangeValue), "max": is(ScoreRangeValue)},
+}
+
+func is(t ValueType) func([]string) ValueType {
+	return func([]string) ValueType {
+		return t
+	}
+}
+
+// zrangeBound returns the type of the range of ZRANGE, which is a score with BYSCORE, a lex range with BYLEX and an index otherwise.
+func zrangeBound(values []string) ValueType {
+	for _, v := range values {
+		switch strings.ToUpper(v) {
+		case "BYSCORE":
+			return ScoreRangeValue
+		case "BYLEX":
+			return LexRangeValue
+		}
+	}
+
+	return IntegerValue
+}
+
+// trimThreshold returns the type of the threshold of XADD and XTRIM, which is a stream ID with MINID and a length with MAXLEN.
+func trimThreshold(values []string) ValueType {
+	for _, v := range values {
+		switch strings.ToUpper(v) {
+		case "MINID":
+			return StreamIDValue
+		case "MAXLEN":
+			return IntegerValue
+		}
+	}
+
+	return AnyValue
+}
+
+// ArgumentType returns the type of the argument of a command from its name in the syntax,
+// values are the arguments of the statement.
+func ArgumentType(command string, name string, values []string) ValueType {
+	if types, ok := commandArgumentTypes[command]; ok {
+		if t, ok := types[name]; ok {
+			return t(values)
+		}
+	}
+
+	return argumentTypes[name]
+}
+
+// ValidValue reports whether the value is of the type.
+func ValidValue(t ValueType, value string) bool {
+	switch t {
+	case IntegerValue:
+		_, err := strconv.ParseInt(value, 10, 64)
+		return err == nil
+	case FloatValue:
+		n, err := strconv.ParseFloat(value, 64)
+		return err == nil && !math.IsNaN(n)
+	case ScoreRangeValue:
+		return ValidValue(FloatValue, strings.TrimPrefix(value, "("))
+	case LexRangeValue:
+		return value == "-" || value == "+" || strings.HasPrefix(value, "[") || strings.HasPrefix(value, "(")
+	case StreamIDValue:
+		return validStreamID(value)
+	}
+
+	return true
+}
+
+// validStreamID reports whether the value is a stream ID (<ms>-<seq> or <ms>) or one of the special IDs,
+// ranges can exclude an ID with "(".
+func validStreamID(value string) bool {
+	switch value {
+	case "*", "$", ">", "-", "+":
+		return true
+	}
+
+	parts := strings.SplitN(strings.TrimPrefix(value, "("), "-", 2)
+	if _, err := strconv.ParseUint(parts[0], 10, 64); err != nil {
+		return false
+	}
+
+	if len(parts) == 1 || parts[1] == "*" {
+		return true
+	}
+
+	_, err := strconv.ParseUint(parts[1], 10, 64)
+
+	return err == nil
+}
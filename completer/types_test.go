@@ -0,0 +1,68 @@
+package completer
+
+import "testing"
+
+func TestValidValue(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Type     ValueType
+		Value    string
+		Expected bool
+	}{
+		{"Integer", IntegerValue, "-10", true},
+		{"Not an integer", IntegerValue, "abc", false},
+		{"Float as integer", IntegerValue, "1.5", false},
+		{"Float", FloatValue, "1.5", true},
+		{"Positive infinity", FloatValue, "+inf", true},
+		{"Negative infinity", FloatValue, "-inf", true},
+		{"Not a number", FloatValue, "nan", false},
+		{"Exclusive score", ScoreRangeValue, "(1.5", true},
+		{"Invalid score", ScoreRangeValue, "[1", false},
+		{"Inclusive lex range", LexRangeValue, "[a", true},
+		{"Exclusive lex range", LexRangeValue, "(b", true},
+		{"Lex range limit", LexRangeValue, "+", true},
+		{"Lex range without bracket", LexRangeValue, "a", false},
+		{"Stream ID", StreamIDValue, "1526919030474-0", true},
+		{"Stream ID without sequence", StreamIDValue, "1526919030474", true},
+		{"Stream ID with any sequence", StreamIDValue, "1526919030474-*", true},
+		{"New stream ID", StreamIDValue, "*", true},
+		{"Last stream ID", StreamIDValue, "$", true},
+		{"Undelivered stream ID", StreamIDValue, ">", true},
+		{"Exclusive stream ID", StreamIDValue, "(1-0", true},
+		{"Invalid stream ID", StreamIDValue, "1-a", false},
+		{"Any value", AnyValue, "abc", true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			if actual := ValidValue(test.Type, test.Value); actual != test.Expected {
+				t.Errorf("%v - Unexpected result: %v (expected %v)", test.Name, actual, test.Expected)
+			}
+		})
+	}
+}
+
+func TestArgumentType(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Command  string
+		Argument string
+		Values   []string
+		Expected ValueType
+	}{
+		{"Type of the name", "EXPIRE", "seconds", nil, IntegerValue},
+		{"Type of the command", "XRANGE", "start", nil, StreamIDValue},
+		{"Range by score", "ZRANGE", "min", []string{"z", "0", "1", "BYSCORE"}, ScoreRangeValue},
+		{"Range by index", "ZRANGE", "min", []string{"z", "0", "1"}, IntegerValue},
+		{"Trim by ID", "XTRIM", "threshold", []string{"s", "MINID", "1-0"}, StreamIDValue},
+		{"Unknown name", "SET", "value", nil, AnyValue},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			if actual := ArgumentType(test.Command, test.Argument, test.Values); actual != test.Expected {
+				t.Errorf("%v - Unexpected result: %v (expected %v)", test.Name, actual, test.Expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,160 @@
+package completer
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Deprecation is a command replaced by another one in a Redis version.
+type Deprecation struct {
+	Since       string
+	Replacement string
+}
+
+// optionVersions are the options added to a command after the command itself by the version they were added in.
+var optionVersions = map[string]map[string]string{
+	"BITCOUNT":             {"BYTE": "7.0.0", "BIT": "7.0.0"},
+	"BITPOS":               {"BYTE": "7.0.0", "BIT": "7.0.0"},
+	"CLIENT KILL":          {"USER": "5.0.0", "LADDR": "6.2.0"},
+	"CLIENT LIST":          {"TYPE": "5.0.0", "ID": "6.2.0"},
+	"CLIENT PAUSE":         {"WRITE": "6.2.0", "ALL": "6.2.0"},
+	"EXPIRE":               {"NX": "7.0.0", "XX": "7.0.0", "GT": "7.0.0", "LT": "7.0.0"},
+	"EXPIREAT":             {"NX": "7.0.0", "XX": "7.0.0", "GT": "7.0.0", "LT": "7.0.0"},
+	"FLUSHALL":             {"ASYNC": "4.0.0", "SYNC": "6.2.0"},
+	"FLUSHDB":              {"ASYNC": "4.0.0", "SYNC": "6.2.0"},
+	"GEOADD":               {"NX": "6.2.0", "XX": "6.2.0", "CH": "6.2.0"},
+	"GEORADIUS":            {"ANY": "6.2.0"},
+	"GEORADIUSBYMEMBER":    {"ANY": "6.2.0"},
+	"GEORADIUSBYMEMBER_RO": {"ANY": "6.2.0"},
+	"GEORADIUS_RO":         {"ANY": "6.2.0"},
+	"MIGRATE":              {"KEYS": "3.0.6", "AUTH": "4.0.7", "AUTH2": "6.0.0"},
+	"PEXPIRE":              {"NX": "7.0.0", "XX": "7.0.0", "GT": "7.0.0", "LT": "7.0.0"},
+	"PEXPIREAT":            {"NX": "7.0.0", "XX": "7.0.0", "GT": "7.0.0", "LT": "7.0.0"},
+	"RESTORE":              {"REPLACE": "3.0.0", "ABSTTL": "5.0.0", "IDLETIME": "5.0.0", "FREQ": "5.0.0"},
+	"SCAN":                 {"TYPE": "6.0.0"},
+	"SCRIPT FLUSH":         {"ASYNC": "6.2.0", "SYNC": "6.2.0"},
+	"SET":                  {"NX": "2.6.12", "XX": "2.6.12", "EX": "2.6.12", "PX": "2.6.12", "KEEPTTL": "6.0.0", "GET": "6.2.0", "EXAT": "6.2.0", "PXAT": "6.2.0"},
+	"SHUTDOWN":             {"NOW": "7.0.0", "FORCE": "7.0.0", "ABORT": "7.0.0"},
+	"XADD":                 {"NOMKSTREAM": "6.2.0", "MINID": "6.2.0", "LIMIT": "6.2.0"},
+	"XCLAIM":               {"LASTID": "7.0.0"},
+	"XGROUP CREATE":        {"MKSTREAM": "5.0.0", "ENTRIESREAD": "7.0.0"},
+	"XGROUP SETID":         {"ENTRIESREAD": "7.0.0"},
+	"XINFO STREAM":         {"FULL": "6.0.0"},
+	"XPENDING":             {"IDLE": "6.2.0"},
+	"XTRIM":                {"MINID": "6.2.0", "LIMIT": "6.2.0"},
+	"ZADD":                 {"NX": "3.0.2", "XX": "3.0.2", "CH": "3.0.2", "INCR": "3.0.2", "GT": "6.2.0", "LT": "6.2.0"},
+	"ZRANGE":               {"BYSCORE": "6.2.0", "BYLEX": "6.2.0", "REV": "6.2.0", "LIMIT": "6.2.0"},
+	"ZRANK":                {"WITHSCORE": "7.2.0"},
+	"ZREVRANK":             {"WITHSCORE": "7.2.0"},
+}
+
+// deprecations are the commands that should not be used in new code with what replaces them.
+var deprecations = map[string]Deprecation{
+	"BRPOPLPUSH":           {"6.2.0", "BLMOVE with RIGHT LEFT"},
+	"CLUSTER SLAVES":       {"5.0.0", "CLUSTER REPLICAS"},
+	"CLUSTER SLOTS":        {"7.0.0", "CLUSTER SHARDS"},
+	"GEORADIUS":            {"6.2.0", "GEOSEARCH or GEOSEARCHSTORE with BYRADIUS"},
+	"GEORADIUSBYMEMBER":    {"6.2.0", "GEOSEARCH or GEOSEARCHSTORE with FROMMEMBER and BYRADIUS"},
+	"GEORADIUSBYMEMBER_RO": {"6.2.0", "GEOSEARCH with FROMMEMBER and BYRADIUS"},
+	"GEORADIUS_RO":         {"6.2.0", "GEOSEARCH with BYRADIUS"},
+	"GETSET":               {"6.2.0", "SET with GET"},
+	"HMSET":                {"4.0.0", "HSET"},
+	"PSETEX":               {"2.6.12", "SET with PX"},
+	"QUIT":                 {"7.2.0", "closing the connection"},
+	"RPOPLPUSH":            {"6.2.0", "LMOVE with RIGHT LEFT"},
+	"SETEX":                {"2.6.12", "SET with EX"},
+	"SETNX":                {"2.6.12", "SET with NX"},
+	"SLAVEOF":              {"5.0.0", "REPLICAOF"},
+	"STRALGO":              {"7.0.0", "LCS"},
+	"ZRANGEBYLEX":          {"6.2.0", "ZRANGE with BYLEX"},
+	"ZRANGEBYSCORE":        {"6.2.0", "ZRANGE with BYSCORE"},
+	"ZREVRANGE":            {"6.2.0", "ZRANGE with REV"},
+	"ZREVRANGEBYLEX":       {"6.2.0", "ZRANGE with REV and BYLEX"},
+	"ZREVRANGEBYSCORE":     {"6.2.0", "ZRANGE with REV and BYSCORE"},
+}
+
+// CommandSince returns the Redis version the command was added in.
+func CommandSince(name string) (string, bool) {
+	c, ok := getCommand(name)
+	return c.Since, ok
+}
+
+// OptionSince returns the Redis version an option was added to the command in,
+// it returns false when the option is as old as the command.
+func OptionSince(command string, option string) (string, bool) {
+	since, ok := optionVersions[command][strings.ToUpper(option)]
+	return since, ok
+}
+
+// Deprecated returns what replaces the command when it is deprecated.
+func Deprecated(command string) (Deprecation, bool) {
+	d, ok := deprecations[command]
+	return d, ok
+}
+
+// CompareVersions compares two Redis versions (e.g. "6.2" and "6.2.0") part by part, missing parts are 0.
+// It returns -1 when a is older than b, 1 when it is newer and 0 when they are the same version.
+func CompareVersions(a string, b string) int {
+	as, bs := versionParts(a), versionParts(b)
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		var x, y int
+		if i < len(as) {
+			x = as[i]
+		}
+
+		if i < len(bs) {
+			y = bs[i]
+		}
+
+		if x < y {
+			return -1
+		}
+
+		if x > y {
+			return 1
+		}
+	}
+
+	return 0
+}
+
+// Available reports whether something added in the since version can be used in the target version,
+// everything is available when the target is unknown.
+func Available(since string, target string) bool {
+	return target == "" || since == "" || CompareVersions(since, target) <= 0
+}
+
+// versionParts returns the numbers of a version, ignoring what follows them (e.g. "7.2.4-rc1" is 7, 2 and 4).
+func versionParts(version string) []int {
+	var parts []int
+	for _, p := range strings.Split(strings.TrimSpace(version), ".") {
+		end := 0
+		for end < len(p) && p[end] >= '0' && p[end] <= '9' {
+			end++
+		}
+
+		n, err := strconv.Atoi(p[:end])
+		if err != nil {
+			break
+		}
+
+		parts = append(parts, n)
+		if end < len(p) {
+			break
+		}
+	}
+
+	return parts
+}
+
+// available returns the commands that can be used in the target version of the completer.
+func (c Completer) available(commands []Command) []Command {
+	var filtered []Command
+	for _, command := range commands {
+		if Available(command.Since, c.TargetVersion) {
+			filtered = append(filtered, command)
+		}
+	}
+
+	return filtered
+}
@@ -0,0 +1,62 @@
+package completer
+
+import "testing"
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		Name     string
+		A        string
+		B        string
+		Expected int
+	}{
+		{"Same version", "6.2.0", "6.2.0", 0},
+		{"Missing patch", "6.2", "6.2.0", 0},
+		{"Older minor", "6.0.16", "6.2.0", -1},
+		{"Newer major", "7.0.0", "6.2.14", 1},
+		{"Two digits", "6.10.0", "6.2.0", 1},
+		{"Release candidate", "7.2.4-rc1", "7.2.4", 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			if actual := CompareVersions(test.A, test.B); actual != test.Expected {
+				t.Errorf("%v - Unexpected result: %v (expected %v)", test.Name, actual, test.Expected)
+			}
+		})
+	}
+}
+
+func TestTargetVersionCompletion(t *testing.T) {
+	tests := []struct {
+		Name           string
+		Text           string
+		TargetVersion  string
+		ExpectedLabels []string
+	}{
+		{"Command too new", "GET", "6.0", []string{"GET", "GETBIT", "GETRANGE", "GETSET"}},
+		{"Command available", "GETE", "6.2", []string{"GETEX"}},
+		{"Options too new", "SET key value ", "6.0", []string{"NX", "XX", "EX", "PX", "KEEPTTL"}},
+		{"Unknown version", "GETE", "", []string{"GETEX"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			items, _ := Completer{Case: AsTyped, TargetVersion: test.TargetVersion}.Complete(test.Text, 0, len(test.Text))
+
+			var labels []string
+			for _, item := range items {
+				labels = append(labels, item.Label)
+			}
+
+			if len(labels) != len(test.ExpectedLabels) {
+				t.Fatalf("%v - Unexpected labels: %v (expected %v)", test.Name, labels, test.ExpectedLabels)
+			}
+
+			for i := range labels {
+				if labels[i] != test.ExpectedLabels[i] {
+					t.Errorf("%v - Unexpected label %v: %v (expected %v)", test.Name, i, labels[i], test.ExpectedLabels[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,186 @@
+package format
+
+import (
+	"strings"
+
+	"github.com/fagnercarvalho/redis-lsp/ast"
+	"github.com/fagnercarvalho/redis-lsp/completer"
+	"github.com/fagnercarvalho/redis-lsp/token"
+)
+
+type Options struct {
+	// Case is how commands and options are written, AsTyped keeps them as they are.
+	Case completer.LetterCase
+
+	// Indent is added once for each MULTI block the statement is in.
+	Indent string
+}
+
+// Format returns the text with one statement per line, normalized spaces and keyword case.
+// Argument values are never changed, only quoted when redis-cli would not read them as they are.
+func Format(text string, options Options) string {
+	return strings.Join(Lines(text, options), lineEnding(text))
+}
+
+// Range formats the lines from start to end (inclusive) of the text.
+func Range(text string, start int, end int, options Options) string {
+	lines := Lines(text, options)
+	if start < 0 {
+		start = 0
+	}
+
+	if end >= len(lines) {
+		end = len(lines) - 1
+	}
+
+	if start > end {
+		return ""
+	}
+
+	return strings.Join(lines[start:end+1], lineEnding(text))
+}
+
+// Lines returns the formatted text of each line of the text,
+// a line with multiple statements is formatted as multiple lines and a line with invalid tokens is kept as it is.
+// Lines of strings with line breaks (e.g. EVAL scripts) are kept as they are.
+func Lines(text string, options Options) []string {
+	ending := lineEnding(text)
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	lines := strings.Split(text, "\n")
+
+	statementsByLine := map[int][]ast.TokenList{}
+	verbatim := map[int]bool{}
+	for _, s := range ast.Parse(text) {
+		statementsByLine[s.Line()] = append(statementsByLine[s.Line()], s)
+
+		for _, t := range s.GetTokens() {
+			for l := t.Line(); l <= ast.EndLine(t) && ast.EndLine(t) > t.Line(); l++ {
+				verbatim[l] = true
+			}
+		}
+	}
+
+	formatted := make([]string, len(lines))
+	depth := 0
+	for i, line := range lines {
+		statements := statementsByLine[i]
+		if hasIllegal(statements) || verbatim[i] {
+			formatted[i] = line
+			continue
+		}
+
+		var result []string
+		for _, s := range statements {
+			if s.GetCommand() == nil {
+				comment := ast.GetComment(s)
+				if comment == nil {
+					continue
+				}
+
+				// a comment after a semicolon stays on the line of the previous statement
+				if len(result) > 0 {
+					result[len(result)-1] += " " + comment.String()
+				} else {
+					result = append(result, strings.Repeat(options.Indent, depth)+comment.String())
+				}
+
+				continue
+			}
+
+			name := ast.CommandName(s)
+			if (name == "EXEC" || name == "DISCARD") && depth > 0 {
+				depth--
+			}
+
+			result = append(result, strings.Repeat(options.Indent, depth)+statement(s, options))
+
+			if name == "MULTI" {
+				depth++
+			}
+		}
+
+		formatted[i] = strings.Join(result, ending)
+	}
+
+	return formatted
+}
+
+// statement formats the command, its arguments and the comment after them separated by a single space.
+func statement(s ast.TokenList, options Options) string {
+	name := ast.CommandName(s)
+	syntax, known := completer.CommandArguments(name)
+
+	var words []string
+	for _, w := range strings.Fields(s.GetCommand().String()) {
+		if known {
+			w = changeCase(w, options.Case)
+		}
+
+		words = append(words, w)
+	}
+
+	arguments := s.GetArguments()
+	var values []string
+	for _, a := range arguments {
+		value, _ := token.Unquote(a.String())
+		values = append(values, value)
+	}
+
+	var matched []*completer.Argument
+	if known {
+		matched = completer.Match(syntax, values)
+	}
+
+	for i, a := range arguments {
+		value := a.String()
+		quoted := strings.HasPrefix(value, "\"") || strings.HasPrefix(value, "'")
+
+		switch {
+		case quoted:
+		case matched != nil && matched[i].Token:
+			// options are case-insensitive so changing their case does not change the command
+			value = changeCase(value, options.Case)
+		case token.NeedsQuotes(value):
+			value = token.Quote(value)
+		}
+
+		words = append(words, value)
+	}
+
+	if comment := ast.GetComment(s); comment != nil {
+		words = append(words, comment.String())
+	}
+
+	return strings.Join(words, " ")
+}
+
+func changeCase(value string, letterCase completer.LetterCase) string {
+	switch letterCase {
+	case completer.UpperCase:
+		return strings.ToUpper(value)
+	case completer.LowerCase:
+		return strings.ToLower(value)
+	}
+
+	return value
+}
+
+func hasIllegal(statements []ast.TokenList) bool {
+	for _, s := range statements {
+		for _, t := range s.GetTokens() {
+			if t.Type() == token.Illegal {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func lineEnding(text string) string {
+	if strings.Contains(text, "\r\n") {
+		return "\r\n"
+	}
+
+	return "\n"
+}
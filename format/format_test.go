@@ -0,0 +1,50 @@
+package format
+
+import (
+	"testing"
+
+	"github.com/fagnercarvalho/redis-lsp/completer"
+)
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		Name         string
+		Text         string
+		Case         completer.LetterCase
+		ExpectedText string
+	}{
+		{"Spaces between arguments", "SET   key    value", completer.UpperCase, "SET key value"},
+		{"Command and option case", "set key value ex 10", completer.UpperCase, "SET key value EX 10"},
+		{"Lower case", "SET key value EX 10", completer.LowerCase, "set key value ex 10"},
+		{"Values are not changed", "set ex ex", completer.UpperCase, "SET ex ex"},
+		{"Multi keyword", "client    list", completer.UpperCase, "CLIENT LIST"},
+		{"Unknown command keeps its case", "foo bar", completer.UpperCase, "foo bar"},
+		{"Statements split by semicolon", "GET a;GET b; GET c;", completer.UpperCase, "GET a\nGET b\nGET c"},
+		{"Quoted values are kept", "SET key \"a  b\" 'c'", completer.UpperCase, "SET key \"a  b\" 'c'"},
+		{"Tab separates arguments", "SET key\tvalue", completer.UpperCase, "SET key value"},
+		{"Value with control character is quoted", "SET key a\x01b", completer.UpperCase, "SET key \"a\\x01b\""},
+		{"Comment after command", "get key    # comment", completer.UpperCase, "GET key # comment"},
+		{"Comment line", "  # comment", completer.UpperCase, "# comment"},
+		{"Transaction", "MULTI\nINCR a\n  INCR b\nEXEC", completer.UpperCase, "MULTI\n  INCR a\n  INCR b\nEXEC"},
+		{"Empty lines are kept", "GET a\n\nGET b\n", completer.UpperCase, "GET a\n\nGET b\n"},
+		{"Line with invalid string is kept", "get   \"open", completer.UpperCase, "get   \"open"},
+		{"String with line breaks is kept", "eval   \"local a = 1\n  return a\"   0\nget   a", completer.UpperCase, "eval   \"local a = 1\n  return a\"   0\nGET a"},
+		{"Windows line endings", "get a\r\nget b", completer.UpperCase, "GET a\r\nGET b"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			text := Format(test.Text, Options{Case: test.Case, Indent: "  "})
+			if text != test.ExpectedText {
+				t.Errorf("expected %q, got %q", test.ExpectedText, text)
+			}
+		})
+	}
+}
+
+func TestRange(t *testing.T) {
+	text := Range("MULTI\nincr   a\nEXEC", 1, 1, Options{Case: completer.UpperCase, Indent: "\t"})
+	if text != "\tINCR a" {
+		t.Errorf("expected %q, got %q", "\tINCR a", text)
+	}
+}
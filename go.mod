@@ -3,6 +3,7 @@ module github.com/fagnercarvalho/redis-lsp
 go 1.16
 
 require (
-	github.com/go-redis/redis/v8 v8.11.1 // indirect
+	github.com/go-redis/redis/v8 v8.11.1
 	github.com/sourcegraph/jsonrpc2 v0.1.0
+	gopkg.in/yaml.v3 v3.0.1
 )
@@ -0,0 +1,50 @@
+package lua
+
+import "strings"
+
+// Header is the keys and arguments declared in the comments at the beginning of a script file
+// to run it with EVAL, for example:
+//
+//	-- KEYS: user:42 user:43
+//	-- ARGV: 10
+type Header struct {
+	Keys      []string
+	Arguments []string
+
+	// Declared is true when the script has a KEYS or an ARGV comment.
+	Declared bool
+}
+
+// ParseHeader returns the keys and arguments declared in the comments before the first statement of the script.
+// Values are separated by spaces.
+func ParseHeader(source string) Header {
+	var header Header
+	for _, line := range strings.Split(source, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		if !strings.HasPrefix(line, "--") {
+			break
+		}
+
+		comment := strings.TrimSpace(strings.TrimPrefix(line, "--"))
+		colon := strings.IndexByte(comment, ':')
+		if colon == -1 {
+			continue
+		}
+
+		values := comment[colon+1:]
+		switch strings.ToUpper(strings.TrimSpace(comment[:colon])) {
+		case "KEYS":
+			header.Keys = append(header.Keys, strings.Fields(values)...)
+			header.Declared = true
+		case "ARGV":
+			header.Arguments = append(header.Arguments, strings.Fields(values)...)
+			header.Declared = true
+		}
+	}
+
+	return header
+}
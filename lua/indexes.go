@@ -0,0 +1,44 @@
+package lua
+
+import "strconv"
+
+// Index is a KEYS[n] or ARGV[n] expression with a number, Start and End are the offsets of the whole expression.
+type Index struct {
+	Name   string
+	Number int
+	Start  int
+	End    int
+}
+
+// Indexes returns the KEYS[n] and ARGV[n] expressions of the script (e.g. KEYS[1] but not KEYS[i]).
+// When the script has an invalid token only the expressions before it are returned.
+func Indexes(source string) []Index {
+	tokens, _ := Tokenize(source)
+
+	var indexes []Index
+	for i := 0; i+3 < len(tokens); i++ {
+		name := tokens[i]
+		if name.Type != Name || (name.Value != "KEYS" && name.Value != "ARGV") {
+			continue
+		}
+
+		// a field of another table (e.g. t.KEYS[1])
+		if i > 0 && tokens[i-1].Type == Symbol && (tokens[i-1].Value == "." || tokens[i-1].Value == ":") {
+			continue
+		}
+
+		open, number, closing := tokens[i+1], tokens[i+2], tokens[i+3]
+		if open.Value != "[" || number.Type != Number || closing.Value != "]" {
+			continue
+		}
+
+		n, err := strconv.Atoi(number.Value)
+		if err != nil {
+			continue
+		}
+
+		indexes = append(indexes, Index{Name: name.Value, Number: n, Start: name.Start, End: closing.End})
+	}
+
+	return indexes
+}
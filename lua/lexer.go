@@ -0,0 +1,348 @@
+// Package lua has a lexer and a syntax checker for the Lua 5.1 scripts run by Redis (EVAL, SCRIPT LOAD and .lua files).
+package lua
+
+import (
+	"fmt"
+	"strings"
+)
+
+type TokenType int
+
+const (
+	Name TokenType = iota
+	Keyword
+	Number
+	String
+	Symbol
+	Comment
+)
+
+// Token is a Lua token, Start and End are byte offsets in the script (End is exclusive).
+type Token struct {
+	Type  TokenType
+	Start int
+	End   int
+	Value string
+}
+
+// Error is a syntax error between the Start and End offsets of the script.
+type Error struct {
+	Start   int
+	End     int
+	Message string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+var keywords = map[string]bool{
+	"and": true, "break": true, "do": true, "else": true, "elseif": true, "end": true,
+	"false": true, "for": true, "function": true, "if": true, "in": true, "local": true,
+	"nil": true, "not": true, "or": true, "repeat": true, "return": true, "then": true,
+	"true": true, "until": true, "while": true,
+}
+
+// symbols are ordered so the longest symbols are matched first
+var symbols = []string{
+	"...", "..", "==", "~=", "<=", ">=",
+	"+", "-", "*", "/", "%", "^", "#", "<", ">", "=",
+	"(", ")", "{", "}", "[", "]", ";", ":", ",", ".",
+}
+
+// Tokenize returns the tokens of the script including comments.
+// When the script has an invalid token (e.g. a string without the closing quote) the tokens until it are returned
+// with the error, a string or comment that is not closed is returned as the last token.
+// The "#!lua name=library" line at the beginning of a Redis function library is returned as a comment.
+func Tokenize(source string) ([]Token, error) {
+	var tokens []Token
+	start := 0
+	if strings.HasPrefix(source, "#!") {
+		start = len(source)
+		if newline := strings.IndexByte(source, '\n'); newline != -1 {
+			start = newline
+		}
+
+		tokens = append(tokens, Token{Type: Comment, Start: 0, End: start, Value: source[:start]})
+	}
+
+	for i := start; i < len(source); {
+		c := source[i]
+
+		switch {
+		case isSpace(c):
+			i++
+			continue
+		case strings.HasPrefix(source[i:], "--"):
+			end, err := comment(source, i)
+			tokens = append(tokens, Token{Type: Comment, Start: i, End: end, Value: source[i:end]})
+			if err != nil {
+				return tokens, err
+			}
+
+			i = end
+		case isLetter(c):
+			end := i + 1
+			for end < len(source) && (isLetter(source[end]) || isDigit(source[end])) {
+				end++
+			}
+
+			tokenType := Name
+			if keywords[source[i:end]] {
+				tokenType = Keyword
+			}
+
+			tokens = append(tokens, Token{Type: tokenType, Start: i, End: end, Value: source[i:end]})
+			i = end
+		case isDigit(c) || (c == '.' && i+1 < len(source) && isDigit(source[i+1])):
+			end, err := number(source, i)
+			if err != nil {
+				return tokens, err
+			}
+
+			tokens = append(tokens, Token{Type: Number, Start: i, End: end, Value: source[i:end]})
+			i = end
+		case c == '"' || c == '\'':
+			end, err := quotedString(source, i)
+			tokens = append(tokens, Token{Type: String, Start: i, End: end, Value: source[i:end]})
+			if err != nil {
+				return tokens, err
+			}
+
+			i = end
+		case c == '[' && longBracket(source, i) != -1:
+			end, err := longString(source, i, "string")
+			tokens = append(tokens, Token{Type: String, Start: i, End: end, Value: source[i:end]})
+			if err != nil {
+				return tokens, err
+			}
+
+			i = end
+		default:
+			symbol := ""
+			for _, s := range symbols {
+				if strings.HasPrefix(source[i:], s) {
+					symbol = s
+					break
+				}
+			}
+
+			if symbol == "" {
+				return tokens, &Error{Start: i, End: i + 1, Message: fmt.Sprintf("unexpected symbol near '%c'", c)}
+			}
+
+			tokens = append(tokens, Token{Type: Symbol, Start: i, End: i + len(symbol), Value: symbol})
+			i += len(symbol)
+		}
+	}
+
+	return tokens, nil
+}
+
+// comment returns the end of a -- comment that goes until the end of the line or a --[[ ]] long comment.
+func comment(source string, start int) (int, error) {
+	if longBracket(source, start+2) != -1 {
+		return longString(source, start+2, "comment")
+	}
+
+	end := strings.IndexByte(source[start:], '\n')
+	if end == -1 {
+		return len(source), nil
+	}
+
+	return start + end, nil
+}
+
+// longBracket returns the level of a long bracket opening at the start (e.g. 2 for [==[) or -1 if there is none.
+func longBracket(source string, start int) int {
+	if start >= len(source) || source[start] != '[' {
+		return -1
+	}
+
+	level := 0
+	for i := start + 1; i < len(source); i++ {
+		switch source[i] {
+		case '=':
+			level++
+		case '[':
+			return level
+		default:
+			return -1
+		}
+	}
+
+	return -1
+}
+
+// longString returns the end of a [[ ]] string or comment.
+func longString(source string, start int, kind string) (int, error) {
+	level := longBracket(source, start)
+	closing := "]" + strings.Repeat("=", level) + "]"
+
+	end := strings.Index(source[start+level+2:], closing)
+	if end == -1 {
+		return len(source), &Error{Start: start, End: len(source), Message: fmt.Sprintf("unfinished long %v near '<eof>'", kind)}
+	}
+
+	return start + level + 2 + end + len(closing), nil
+}
+
+// quotedString returns the end of a single or double quoted string, which can not have line breaks unless they are escaped.
+func quotedString(source string, start int) (int, error) {
+	quote := source[start]
+	for i := start + 1; i < len(source); i++ {
+		switch source[i] {
+		case quote:
+			return i + 1, nil
+		case '\n', '\r':
+			return i, &Error{Start: start, End: i, Message: fmt.Sprintf("unfinished string near '%v'", source[start:i])}
+		case '\\':
+			i++
+		}
+	}
+
+	return len(source), &Error{Start: start, End: len(source), Message: "unfinished string near '<eof>'"}
+}
+
+// number returns the end of a number (e.g. 10, 3.14, 1e-3 or 0xff).
+func number(source string, start int) (int, error) {
+	end := start
+	for end < len(source) {
+		c := source[end]
+		if (c == '-' || c == '+') && (source[end-1] == 'e' || source[end-1] == 'E') && !isHexNumber(source[start:end]) {
+			end++
+			continue
+		}
+
+		if !isDigit(c) && !isLetter(c) && c != '.' {
+			break
+		}
+
+		end++
+	}
+
+	if !validNumber(source[start:end]) {
+		return end, &Error{Start: start, End: end, Message: fmt.Sprintf("malformed number near '%v'", source[start:end])}
+	}
+
+	return end, nil
+}
+
+func validNumber(value string) bool {
+	if isHexNumber(value) {
+		if len(value) == 2 {
+			return false
+		}
+
+		for i := 2; i < len(value); i++ {
+			if !isHexDigit(value[i]) {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	digits, dot, exponent := 0, false, false
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		switch {
+		case isDigit(c):
+			digits++
+		case c == '.' && !dot && !exponent:
+			dot = true
+		case (c == 'e' || c == 'E') && !exponent && digits > 0:
+			exponent = true
+			digits = 0
+			if i+1 < len(value) && (value[i+1] == '-' || value[i+1] == '+') {
+				i++
+			}
+		default:
+			return false
+		}
+	}
+
+	return digits > 0
+}
+
+// StringValue returns the value of a String token without the quotes and with the escape sequences replaced.
+func StringValue(t Token) string {
+	value := t.Value
+	if level := longBracket(value, 0); level != -1 {
+		value = strings.TrimPrefix(value[level+2:], "\n")
+		return strings.TrimSuffix(value, "]"+strings.Repeat("=", level)+"]")
+	}
+
+	if value == "" {
+		return ""
+	}
+
+	quote := value[0]
+	var result strings.Builder
+	for i := 1; i < len(value); i++ {
+		c := value[i]
+		if c == quote {
+			break
+		}
+
+		if c != '\\' || i+1 >= len(value) {
+			result.WriteByte(c)
+			continue
+		}
+
+		i++
+		switch value[i] {
+		case 'n':
+			result.WriteByte('\n')
+		case 'r':
+			result.WriteByte('\r')
+		case 't':
+			result.WriteByte('\t')
+		case 'a':
+			result.WriteByte('\a')
+		case 'b':
+			result.WriteByte('\b')
+		case 'f':
+			result.WriteByte('\f')
+		case 'v':
+			result.WriteByte('\v')
+		default:
+			if !isDigit(value[i]) {
+				result.WriteByte(value[i])
+				continue
+			}
+
+			// \ddd is a byte with up to three decimal digits
+			code := 0
+			for j := 0; j < 3 && i < len(value) && isDigit(value[i]); j++ {
+				code = code*10 + int(value[i]-'0')
+				i++
+			}
+
+			i--
+			result.WriteByte(byte(code))
+		}
+	}
+
+	return result.String()
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f' || c == '\v'
+}
+
+func isLetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c == '_'
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isHexDigit(c byte) bool {
+	return isDigit(c) || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+}
+
+func isHexNumber(value string) bool {
+	return strings.HasPrefix(value, "0x") || strings.HasPrefix(value, "0X")
+}
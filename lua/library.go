@@ -0,0 +1,94 @@
+package lua
+
+import "strings"
+
+// Library is a Redis function library, a script that starts with a "#!lua name=library" line
+// and registers its functions with redis.register_function to call them with FCALL.
+type Library struct {
+	Name      string
+	Engine    string
+	Functions []Function
+}
+
+// Function is a function registered by a library, Start and End are the offsets of the string with its name (End is exclusive).
+type Function struct {
+	Name  string
+	Start int
+	End   int
+}
+
+// ParseLibrary returns the library declared in the first line of the script, ok is false when the script is not a library.
+// The name is empty when the line does not have it.
+func ParseLibrary(source string) (Library, bool) {
+	if !strings.HasPrefix(source, "#!") {
+		return Library{}, false
+	}
+
+	line := source[2:]
+	if newline := strings.IndexByte(line, '\n'); newline != -1 {
+		line = line[:newline]
+	}
+
+	var library Library
+	fields := strings.Fields(line)
+	if len(fields) > 0 {
+		library.Engine = fields[0]
+	}
+
+	for _, f := range fields {
+		if strings.HasPrefix(f, "name=") {
+			library.Name = strings.TrimPrefix(f, "name=")
+		}
+	}
+
+	library.Functions = registeredFunctions(source)
+
+	return library, true
+}
+
+// registeredFunctions returns the functions registered with redis.register_function('name', callback)
+// or redis.register_function{function_name='name', callback=callback}.
+func registeredFunctions(source string) []Function {
+	tokens, _ := Tokenize(source)
+
+	var code []Token
+	for _, t := range tokens {
+		if t.Type != Comment {
+			code = append(code, t)
+		}
+	}
+
+	var functions []Function
+	for i := 0; i+3 < len(code); i++ {
+		if code[i].Value != "redis" || code[i+1].Value != "." || code[i+2].Value != "register_function" {
+			continue
+		}
+
+		switch code[i+3].Value {
+		case "(":
+			if i+4 < len(code) && code[i+4].Type == String {
+				functions = append(functions, registeredFunction(code[i+4]))
+			}
+		case "{":
+			depth := 0
+			for j := i + 4; j < len(code) && depth >= 0; j++ {
+				switch code[j].Value {
+				case "{", "(", "[":
+					depth++
+				case "}", ")", "]":
+					depth--
+				case "function_name":
+					if depth == 0 && j+2 < len(code) && code[j+1].Value == "=" && code[j+2].Type == String {
+						functions = append(functions, registeredFunction(code[j+2]))
+					}
+				}
+			}
+		}
+	}
+
+	return functions
+}
+
+func registeredFunction(name Token) Function {
+	return Function{Name: StringValue(name), Start: name.Start, End: name.End}
+}
@@ -0,0 +1,455 @@
+package lua
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Check returns the first syntax error of the script or nil if it is valid Lua 5.1.
+// The error messages follow the ones returned by Redis (e.g. "'end' expected near '<eof>'").
+func Check(source string) error {
+	tokens, err := Tokenize(source)
+	if err != nil {
+		return err
+	}
+
+	p := parser{source: source}
+	for _, t := range tokens {
+		if t.Type != Comment {
+			p.tokens = append(p.tokens, t)
+		}
+	}
+
+	return p.parse()
+}
+
+type parser struct {
+	source string
+	tokens []Token
+	index  int
+}
+
+func (p *parser) parse() (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			e, ok := r.(*Error)
+			if !ok {
+				panic(r)
+			}
+
+			err = e
+		}
+	}()
+
+	p.block()
+	if !p.eof() {
+		p.fail(fmt.Sprintf("'<eof>' expected near %v", p.near()))
+	}
+
+	return nil
+}
+
+// block parses statements until the end of the block (e.g. "end" or "until"), return and break must be the last statement.
+func (p *parser) block() {
+	for !p.blockEnd() {
+		if p.is(Keyword, "return") {
+			p.next()
+			if !p.blockEnd() && !p.is(Symbol, ";") {
+				p.expressions()
+			}
+
+			p.accept(Symbol, ";")
+			return
+		}
+
+		if p.accept(Keyword, "break") {
+			p.accept(Symbol, ";")
+			return
+		}
+
+		p.statement()
+		p.accept(Symbol, ";")
+	}
+}
+
+func (p *parser) blockEnd() bool {
+	if p.eof() {
+		return true
+	}
+
+	t := p.peek()
+	if t.Type != Keyword {
+		return false
+	}
+
+	switch t.Value {
+	case "else", "elseif", "end", "until":
+		return true
+	}
+
+	return false
+}
+
+func (p *parser) statement() {
+	t := p.peek()
+	if t.Type == Keyword {
+		switch t.Value {
+		case "if":
+			p.next()
+			p.expression()
+			p.expect(Keyword, "then")
+			p.block()
+			for p.accept(Keyword, "elseif") {
+				p.expression()
+				p.expect(Keyword, "then")
+				p.block()
+			}
+
+			if p.accept(Keyword, "else") {
+				p.block()
+			}
+
+			p.match("end", t)
+			return
+		case "while":
+			p.next()
+			p.expression()
+			p.expect(Keyword, "do")
+			p.block()
+			p.match("end", t)
+			return
+		case "do":
+			p.next()
+			p.block()
+			p.match("end", t)
+			return
+		case "for":
+			p.next()
+			p.name()
+			if p.accept(Symbol, "=") {
+				p.expression()
+				p.expect(Symbol, ",")
+				p.expression()
+				if p.accept(Symbol, ",") {
+					p.expression()
+				}
+			} else {
+				for p.accept(Symbol, ",") {
+					p.name()
+				}
+
+				if !p.is(Keyword, "in") {
+					p.fail(fmt.Sprintf("'=' or 'in' expected near %v", p.near()))
+				}
+
+				p.next()
+				p.expressions()
+			}
+
+			p.expect(Keyword, "do")
+			p.block()
+			p.match("end", t)
+			return
+		case "repeat":
+			p.next()
+			p.block()
+			p.match("until", t)
+			p.expression()
+			return
+		case "function":
+			p.next()
+			p.name()
+			for p.accept(Symbol, ".") {
+				p.name()
+			}
+
+			if p.accept(Symbol, ":") {
+				p.name()
+			}
+
+			p.functionBody(t)
+			return
+		case "local":
+			p.next()
+			if function := p.peek(); p.accept(Keyword, "function") {
+				p.name()
+				p.functionBody(function)
+				return
+			}
+
+			p.name()
+			for p.accept(Symbol, ",") {
+				p.name()
+			}
+
+			if p.accept(Symbol, "=") {
+				p.expressions()
+			}
+
+			return
+		}
+	}
+
+	// an assignment or a function call
+	call := p.suffixedExpression()
+	if p.is(Symbol, "=") || p.is(Symbol, ",") {
+		if call {
+			p.fail(fmt.Sprintf("syntax error near %v", p.near()))
+		}
+
+		for p.accept(Symbol, ",") {
+			if p.suffixedExpression() {
+				p.fail(fmt.Sprintf("syntax error near %v", p.near()))
+			}
+		}
+
+		p.expect(Symbol, "=")
+		p.expressions()
+		return
+	}
+
+	if !call {
+		p.fail(fmt.Sprintf("syntax error near %v", p.near()))
+	}
+}
+
+func (p *parser) functionBody(function Token) {
+	p.expect(Symbol, "(")
+	if !p.is(Symbol, ")") {
+		for {
+			if p.accept(Symbol, "...") {
+				break
+			}
+
+			p.name()
+			if !p.accept(Symbol, ",") {
+				break
+			}
+		}
+	}
+
+	p.expect(Symbol, ")")
+	p.block()
+	p.match("end", function)
+}
+
+func (p *parser) expressions() {
+	p.expression()
+	for p.accept(Symbol, ",") {
+		p.expression()
+	}
+}
+
+var binaryOperators = map[string]bool{
+	"+": true, "-": true, "*": true, "/": true, "%": true, "^": true, "..": true,
+	"==": true, "~=": true, "<": true, "<=": true, ">": true, ">=": true, "and": true, "or": true,
+}
+
+// expression parses an expression, the precedence of the operators is not needed to check the syntax.
+func (p *parser) expression() {
+	for {
+		for p.accept(Keyword, "not") || p.accept(Symbol, "-") || p.accept(Symbol, "#") {
+			// unary operators can be repeated (e.g. not not x)
+		}
+
+		p.simpleExpression()
+
+		t := p.peek()
+		if p.eof() || (t.Type != Symbol && t.Type != Keyword) || !binaryOperators[t.Value] {
+			return
+		}
+
+		p.next()
+	}
+}
+
+func (p *parser) simpleExpression() {
+	t := p.peek()
+	switch {
+	case t.Type == Number || t.Type == String:
+		p.next()
+	case t.Type == Keyword && (t.Value == "nil" || t.Value == "true" || t.Value == "false"):
+		p.next()
+	case t.Type == Symbol && t.Value == "...":
+		p.next()
+	case t.Type == Symbol && t.Value == "{":
+		p.table()
+	case t.Type == Keyword && t.Value == "function":
+		p.next()
+		p.functionBody(t)
+	default:
+		p.suffixedExpression()
+	}
+}
+
+// suffixedExpression parses a variable or a function call (e.g. redis.call('GET', KEYS[1])) and reports whether it is a call.
+func (p *parser) suffixedExpression() bool {
+	switch {
+	case p.is(Name, ""):
+		p.next()
+	case p.is(Symbol, "("):
+		open := p.next()
+		p.expression()
+		p.match(")", open)
+	default:
+		p.fail(fmt.Sprintf("unexpected symbol near %v", p.near()))
+	}
+
+	call := false
+	for !p.eof() {
+		t := p.peek()
+		switch {
+		case t.Type == Symbol && t.Value == ".":
+			p.next()
+			p.name()
+			call = false
+		case t.Type == Symbol && t.Value == "[":
+			p.next()
+			p.expression()
+			p.expect(Symbol, "]")
+			call = false
+		case t.Type == Symbol && t.Value == ":":
+			p.next()
+			p.name()
+			p.arguments()
+			call = true
+		case (t.Type == Symbol && (t.Value == "(" || t.Value == "{")) || t.Type == String:
+			p.arguments()
+			call = true
+		default:
+			return call
+		}
+	}
+
+	return call
+}
+
+func (p *parser) arguments() {
+	t := p.peek()
+	switch {
+	case t.Type == String:
+		p.next()
+	case t.Type == Symbol && t.Value == "{":
+		p.table()
+	case t.Type == Symbol && t.Value == "(":
+		p.next()
+		if !p.is(Symbol, ")") {
+			p.expressions()
+		}
+
+		p.match(")", t)
+	default:
+		p.fail(fmt.Sprintf("function arguments expected near %v", p.near()))
+	}
+}
+
+func (p *parser) table() {
+	open := p.next()
+	for !p.is(Symbol, "}") {
+		switch {
+		case p.is(Symbol, "["):
+			p.next()
+			p.expression()
+			p.expect(Symbol, "]")
+			p.expect(Symbol, "=")
+			p.expression()
+		case p.is(Name, "") && p.index+1 < len(p.tokens) && p.tokens[p.index+1].Type == Symbol && p.tokens[p.index+1].Value == "=":
+			p.next()
+			p.next()
+			p.expression()
+		default:
+			p.expression()
+		}
+
+		if !p.accept(Symbol, ",") && !p.accept(Symbol, ";") {
+			break
+		}
+	}
+
+	p.match("}", open)
+}
+
+func (p *parser) name() {
+	if !p.is(Name, "") {
+		p.fail(fmt.Sprintf("<name> expected near %v", p.near()))
+	}
+
+	p.next()
+}
+
+// match expects the token closing a block or parentheses, the error tells where the block was opened if it is in another line.
+func (p *parser) match(value string, open Token) {
+	if p.is(Keyword, value) || p.is(Symbol, value) {
+		p.next()
+		return
+	}
+
+	line := p.line(open.Start)
+	if p.line(p.peek().Start) != line {
+		p.fail(fmt.Sprintf("'%v' expected (to close '%v' at line %v) near %v", value, open.Value, line, p.near()))
+	}
+
+	p.fail(fmt.Sprintf("'%v' expected near %v", value, p.near()))
+}
+
+func (p *parser) expect(tokenType TokenType, value string) {
+	if !p.accept(tokenType, value) {
+		p.fail(fmt.Sprintf("'%v' expected near %v", value, p.near()))
+	}
+}
+
+func (p *parser) accept(tokenType TokenType, value string) bool {
+	if !p.is(tokenType, value) {
+		return false
+	}
+
+	p.next()
+	return true
+}
+
+// is reports whether the current token has the type and the value (any value if it is empty).
+func (p *parser) is(tokenType TokenType, value string) bool {
+	if p.eof() {
+		return false
+	}
+
+	t := p.peek()
+	return t.Type == tokenType && (value == "" || t.Value == value)
+}
+
+func (p *parser) eof() bool {
+	return p.index >= len(p.tokens)
+}
+
+func (p *parser) peek() Token {
+	if p.eof() {
+		return Token{Start: len(p.source), End: len(p.source)}
+	}
+
+	return p.tokens[p.index]
+}
+
+func (p *parser) next() Token {
+	t := p.peek()
+	p.index++
+	return t
+}
+
+// near returns how the current token is shown in the error messages.
+func (p *parser) near() string {
+	if p.eof() {
+		return "'<eof>'"
+	}
+
+	return fmt.Sprintf("'%v'", p.peek().Value)
+}
+
+// line returns the line of an offset in the script starting from 1 like Lua does.
+func (p *parser) line(offset int) int {
+	return strings.Count(p.source[:offset], "\n") + 1
+}
+
+func (p *parser) fail(message string) {
+	t := p.peek()
+	panic(&Error{Start: t.Start, End: t.End, Message: message})
+}
@@ -0,0 +1,209 @@
+package lua
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		Name           string
+		Source         string
+		ExpectedValues []string
+		ExpectedError  string
+	}{
+		{"Call", "redis.call('GET', KEYS[1])", []string{"redis", ".", "call", "(", "'GET'", ",", "KEYS", "[", "1", "]", ")"}, ""},
+		{"Comments", "-- comment\nx = 1 --[[ long\ncomment ]] y = 2", []string{"-- comment", "x", "=", "1", "--[[ long\ncomment ]]", "y", "=", "2"}, ""},
+		{"Long string", "x = [==[a]]b]==]", []string{"x", "=", "[==[a]]b]==]"}, ""},
+		{"Numbers", "1 3.14 .5 1e-3 0xff", []string{"1", "3.14", ".5", "1e-3", "0xff"}, ""},
+		{"Operators", "a..b ~= c ... #d", []string{"a", "..", "b", "~=", "c", "...", "#", "d"}, ""},
+		{"Escaped quote", `x = 'it\'s'`, []string{"x", "=", `'it\'s'`}, ""},
+		{"Unfinished string", "x = 'abc", []string{"x", "=", "'abc"}, "unfinished string near '<eof>'"},
+		{"Unfinished string at line end", "x = 'abc\ny", []string{"x", "=", "'abc"}, "unfinished string near ''abc'"},
+		{"Malformed number", "x = 3x", []string{"x", "="}, "malformed number near '3x'"},
+		{"Unexpected symbol", "x = @", []string{"x", "="}, "unexpected symbol near '@'"},
+		{"Library", "#!lua name=lib\nreturn 1", []string{"#!lua name=lib", "return", "1"}, ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			tokens, err := Tokenize(test.Source)
+
+			message := ""
+			if err != nil {
+				message = err.Error()
+			}
+
+			if message != test.ExpectedError {
+				t.Errorf("%v - Unexpected error: %q (expected %q)", test.Name, message, test.ExpectedError)
+			}
+
+			var values []string
+			for _, token := range tokens {
+				values = append(values, token.Value)
+			}
+
+			if len(values) != len(test.ExpectedValues) {
+				t.Fatalf("%v - Unexpected tokens: %q (expected %q)", test.Name, values, test.ExpectedValues)
+			}
+
+			for i := range values {
+				if values[i] != test.ExpectedValues[i] {
+					t.Errorf("%v - Unexpected token %v: %q (expected %q)", test.Name, i, values[i], test.ExpectedValues[i])
+				}
+			}
+		})
+	}
+}
+
+func TestStringValue(t *testing.T) {
+	tests := map[string]string{
+		`'GET'`:       "GET",
+		`"a\"b"`:      `a"b`,
+		`'a\nb'`:      "a\nb",
+		`'\65\066'`:   "AB",
+		"[[\nline]]":  "line",
+		"[=[a]]b]=]":  "a]]b",
+		`'unfinished`: "unfinished",
+	}
+
+	for value, expected := range tests {
+		tokens, _ := Tokenize(value)
+		if actual := StringValue(tokens[0]); actual != expected {
+			t.Errorf("Unexpected value of %v: %q (expected %q)", value, actual, expected)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		Name          string
+		Source        string
+		ExpectedError string
+		ExpectedStart int
+	}{
+		{"Call", "return redis.call('GET', KEYS[1])", "", 0},
+		{"Script", `
+local current = redis.call('INCR', KEYS[1])
+if tonumber(current) == 1 then
+  redis.call('EXPIRE', KEYS[1], ARGV[1])
+elseif current > 10 then
+  return redis.error_reply('too many')
+else
+  redis.log(redis.LOG_NOTICE, "count " .. current)
+end
+for i, key in ipairs(KEYS) do
+  redis.call('DEL', key)
+end
+for i = 1, #ARGV, 2 do
+  local t = {ARGV[i], [1] = 2, name = "x"; f = function(...) return ... end}
+end
+local function add(a, b) return a + b end
+repeat current = current - 1 until not (current > 0)
+while true do break end
+do local a, b = 1, -2 end
+return {add(1, 2), #KEYS}`, "", 0},
+		{"Method call", "local s = ('x'):rep(3) return s:upper()", "", 0},
+		{"Missing end", "if x then\nreturn 1", "'end' expected (to close 'if' at line 1) near '<eof>'", 18},
+		{"Missing then", "if x return 1 end", "'then' expected near 'return'", 5},
+		{"Missing closing parenthesis", "return redis.call('GET', KEYS[1]", "')' expected near '<eof>'", 32},
+		{"Expression statement", "x + 1", "syntax error near '+'", 2},
+		{"Assignment to a call", "f() = 1", "syntax error near '='", 4},
+		{"Statement after return", "return 1 x = 2", "'<eof>' expected near 'x'", 9},
+		{"Unexpected symbol", "return = 1", "unexpected symbol near '='", 7},
+		{"Missing name", "local = 1", "<name> expected near '='", 6},
+		{"Lexer error", "return 'abc", "unfinished string near '<eof>'", 7},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			err := Check(test.Source)
+			if test.ExpectedError == "" {
+				if err != nil {
+					t.Errorf("%v - Unexpected error: %v", test.Name, err)
+				}
+
+				return
+			}
+
+			e, ok := err.(*Error)
+			if !ok {
+				t.Fatalf("%v - Expected error %q but got %v", test.Name, test.ExpectedError, err)
+			}
+
+			if e.Message != test.ExpectedError || e.Start != test.ExpectedStart {
+				t.Errorf("%v - Unexpected error: %q at %v (expected %q at %v)", test.Name, e.Message, e.Start, test.ExpectedError, test.ExpectedStart)
+			}
+		})
+	}
+}
+
+func TestIndexes(t *testing.T) {
+	source := "local a = KEYS[1] .. ARGV[ 2 ]\nlocal b = KEYS[i], t.KEYS[3], ARGV[1.5]\nreturn KEYS[10]"
+	expected := []Index{
+		{Name: "KEYS", Number: 1, Start: 10, End: 17},
+		{Name: "ARGV", Number: 2, Start: 21, End: 30},
+		{Name: "KEYS", Number: 10, Start: 78, End: 86},
+	}
+
+	indexes := Indexes(source)
+	if len(indexes) != len(expected) {
+		t.Fatalf("Unexpected indexes: %+v (expected %+v)", indexes, expected)
+	}
+
+	for i := range indexes {
+		if indexes[i] != expected[i] {
+			t.Errorf("Unexpected index %v: %+v (expected %+v)", i, indexes[i], expected[i])
+		}
+	}
+}
+
+func TestParseHeader(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Source   string
+		Expected Header
+	}{
+		{"Keys and arguments", "-- Increments a counter\n-- KEYS: counter:1 counter:2\n--argv: 10\nreturn 1", Header{Keys: []string{"counter:1", "counter:2"}, Arguments: []string{"10"}, Declared: true}},
+		{"Empty keys", "-- KEYS:\nreturn 1", Header{Declared: true}},
+		{"After the first statement", "local x = 1\n-- KEYS: a", Header{}},
+		{"No header", "return 1", Header{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			header := ParseHeader(test.Source)
+			if !reflect.DeepEqual(header, test.Expected) {
+				t.Errorf("%v - Unexpected header: %+v (expected %+v)", test.Name, header, test.Expected)
+			}
+		})
+	}
+}
+
+func TestParseLibrary(t *testing.T) {
+	tests := []struct {
+		Name       string
+		Source     string
+		Expected   Library
+		ExpectedOk bool
+	}{
+		{
+			"Functions",
+			"#!lua name=mylib\nredis.register_function('knockknock', function() return 'Who\\'s there?' end)\n" +
+				"redis.register_function{function_name='my_hset', callback=function(keys, args) return 1 end, flags={'no-writes'}}",
+			Library{Name: "mylib", Engine: "lua", Functions: []Function{{"knockknock", 41, 53}, {"my_hset", 132, 141}}},
+			true,
+		},
+		{"No name", "#!lua\nreturn 1", Library{Engine: "lua"}, true},
+		{"Script", "return redis.register_function('f', f)", Library{}, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			library, ok := ParseLibrary(test.Source)
+			if ok != test.ExpectedOk || !reflect.DeepEqual(library, test.Expected) {
+				t.Errorf("%v - Unexpected library: %+v, %v (expected %+v, %v)", test.Name, library, ok, test.Expected, test.ExpectedOk)
+			}
+		})
+	}
+}
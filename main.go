@@ -8,6 +8,7 @@ import (
 	"io"
 	"log"
 	"os"
+	"path/filepath"
 )
 
 func main() {
@@ -18,13 +19,13 @@ func main() {
 	flag.StringVar(&username, "username", "", "Redis instance username for caching data for autocompletion.")
 	flag.StringVar(&password, "password", "", "Redis instance password for caching data for autocompletion.")
 	flag.IntVar(&database, "database", 0, "Redis database for caching data for autocompletion.")
-	flag.StringVar(&logFile, "logFile", "c:/server.log", "Path for log file.")
+	flag.StringVar(&logFile, "logFile", filepath.Join(os.TempDir(), "redis-lsp.log"), "Path for log file.")
 	flag.BoolVar(&debugLogEnabled, "debugLogEnabled", false, "Enables debug logging.")
 	flag.BoolVar(&dbCacheEnabled, "dbCacheEnabled", false, "Enables keys and users autocompletion.")
 	flag.Parse()
 
 	if debugLogEnabled {
-		f, err := os.OpenFile("c:/server.log", os.O_CREATE|os.O_RDWR|os.O_APPEND, 0660)
+		f, err := os.OpenFile(logFile, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0660)
 		if err != nil {
 			log.Fatal(err)
 		}
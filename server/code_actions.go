@@ -0,0 +1,296 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/fagnercarvalho/redis-lsp/analysis"
+	"github.com/fagnercarvalho/redis-lsp/ast"
+	"github.com/fagnercarvalho/redis-lsp/completer"
+	"github.com/fagnercarvalho/redis-lsp/token"
+)
+
+// deprecatedRewrites return the words of the statement that replaces a deprecated command from its arguments,
+// ok is false when the statement does not have the arguments of the command (e.g. while it is being written).
+var deprecatedRewrites = map[string]func(arguments []string) ([]string, bool){
+	"BRPOPLPUSH": func(a []string) ([]string, bool) {
+		return []string{"BLMOVE", at(a, 0), at(a, 1), "RIGHT", "LEFT", at(a, 2)}, len(a) == 3
+	},
+	"CLUSTER SLAVES": renamed("CLUSTER REPLICAS"),
+	"GETSET": func(a []string) ([]string, bool) {
+		return []string{"SET", at(a, 0), at(a, 1), "GET"}, len(a) == 2
+	},
+	"HMSET": renamed("HSET"),
+	"PSETEX": func(a []string) ([]string, bool) {
+		return []string{"SET", at(a, 0), at(a, 2), "PX", at(a, 1)}, len(a) == 3
+	},
+	"RPOPLPUSH": func(a []string) ([]string, bool) {
+		return []string{"LMOVE", at(a, 0), at(a, 1), "RIGHT", "LEFT"}, len(a) == 2
+	},
+	"SETEX": func(a []string) ([]string, bool) {
+		return []string{"SET", at(a, 0), at(a, 2), "EX", at(a, 1)}, len(a) == 3
+	},
+	"SETNX": func(a []string) ([]string, bool) {
+		return []string{"SET", at(a, 0), at(a, 1), "NX"}, len(a) == 2
+	},
+	"SLAVEOF":          renamed("REPLICAOF"),
+	"ZRANGEBYLEX":      zrange("BYLEX"),
+	"ZRANGEBYSCORE":    zrange("BYSCORE"),
+	"ZREVRANGE":        zrange("REV"),
+	"ZREVRANGEBYLEX":   zrange("BYLEX", "REV"),
+	"ZREVRANGEBYSCORE": zrange("BYSCORE", "REV"),
+}
+
+// renamed replaces a command that only changed its name.
+func renamed(command string) func([]string) ([]string, bool) {
+	return func(a []string) ([]string, bool) {
+		return append([]string{command}, a...), true
+	}
+}
+
+// zrange replaces a ZRANGE variant with ZRANGE and the options of the variant after the range.
+func zrange(options ...string) func([]string) ([]string, bool) {
+	return func(a []string) ([]string, bool) {
+		if len(a) < 3 {
+			return nil, false
+		}
+
+		words := append([]string{"ZRANGE"}, a[:3]...)
+		words = append(words, options...)
+
+		return append(words, a[3:]...), true
+	}
+}
+
+func at(values []string, index int) string {
+	if index < len(values) {
+		return values[index]
+	}
+
+	return ""
+}
+
+// handleCodeAction returns the quick fixes for the diagnostics in the requested range and the refactors of the selected statements.
+func (s Server) handleCodeAction(params *json.RawMessage) (interface{}, error) {
+	var request CodeActionParams
+	err := json.Unmarshal(*params, &request)
+	if err != nil {
+		return nil, err
+	}
+
+	uri := request.TextDocument.Uri
+	if isScript(uri) {
+		return []CodeAction{}, nil
+	}
+
+	text := s.files[uri]
+	statements := ast.Parse(text)
+
+	actions := []CodeAction{}
+	for _, d := range s.analyze(uri) {
+		if !s.settings.Diagnostics.diagnosticEnabled(d.Code) || d.Line < request.Range.Start.Line || d.Line > request.Range.End.Line {
+			continue
+		}
+
+		title, edits, ok := quickFix(text, statements, d)
+		if !ok || !requestedKind(request.Context.Only, QuickFix) {
+			continue
+		}
+
+		actions = append(actions, CodeAction{
+			Title:       title,
+			Kind:        QuickFix,
+			Diagnostics: []Diagnostic{lspDiagnostic(d)},
+			IsPreferred: true,
+			Edit:        &WorkspaceEdit{Changes: map[string][]TextEdit{uri: edits}},
+		})
+	}
+
+	for _, r := range refactors(text, statements, request.Range) {
+		if !requestedKind(request.Context.Only, r.Kind) {
+			continue
+		}
+
+		actions = append(actions, CodeAction{
+			Title: r.Title,
+			Kind:  r.Kind,
+			Edit:  &WorkspaceEdit{Changes: map[string][]TextEdit{uri: r.Edits}},
+		})
+	}
+
+	return actions, nil
+}
+
+// requestedKind reports whether the client asked for the kind of code action,
+// asking for a kind includes its subkinds (e.g. "refactor" includes "refactor.extract").
+func requestedKind(only []CodeActionKind, kind CodeActionKind) bool {
+	if len(only) == 0 {
+		return true
+	}
+
+	for _, o := range only {
+		if kind == o || strings.HasPrefix(string(kind), string(o)+".") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// quickFix returns the title and the edits of the fix for a diagnostic, ok is false when the diagnostic has no fix.
+func quickFix(text string, statements []ast.TokenList, d analysis.Diagnostic) (string, []TextEdit, bool) {
+	diagnosticRange := Range{Start: Position{Line: d.Line, Character: d.Start}, End: Position{Line: d.Line, Character: d.End}}
+
+	switch d.Code {
+	case "unknown-command":
+		statement, node, ok := diagnosticNode(statements, d)
+		if !ok {
+			return "", nil, false
+		}
+
+		isCommand := node.Start() == statement.GetCommand().Start()
+
+		name := strings.ToUpper(node.String())
+		if !isCommand {
+			name = ast.CommandName(statement) + " " + name
+		}
+
+		similar, ok := completer.SimilarCommand(name)
+		if !ok {
+			return "", nil, false
+		}
+
+		// only the subcommand is replaced when the container is known
+		words := strings.Fields(similar)
+		replacement := sameCase(node.String(), words[len(words)-1])
+		if isCommand {
+			replacement = sameCase(node.String(), similar)
+		}
+
+		return fmt.Sprintf("Change %v to %v", node.String(), replacement), []TextEdit{{Range: nodeRange(node), NewText: replacement}}, true
+	case "deprecated-command":
+		statement, _, ok := diagnosticNode(statements, d)
+		if !ok {
+			return "", nil, false
+		}
+
+		return deprecatedFix(statement)
+	case "unclosed-multi":
+		return "Add EXEC at the end of the transaction", []TextEdit{transactionEnd(text, statements, d.Line)}, true
+	case "unquoted-spaces":
+		lines := strings.Split(text, "\n")
+		if d.Line >= len(lines) || d.End > len(lines[d.Line]) {
+			return "", nil, false
+		}
+
+		value := lines[d.Line][d.Start:d.End]
+
+		return fmt.Sprintf("Quote %v", value), []TextEdit{{Range: diagnosticRange, NewText: token.Quote(value)}}, true
+	}
+
+	return "", nil, false
+}
+
+// deprecatedFix replaces the statement of a deprecated command with the statement of the command that replaces it.
+func deprecatedFix(statement ast.TokenList) (string, []TextEdit, bool) {
+	command := ast.CommandName(statement)
+	rewrite, ok := deprecatedRewrites[command]
+	if !ok {
+		return "", nil, false
+	}
+
+	arguments := statement.GetArguments()
+	var values []string
+	isArgument := map[string]bool{}
+	for _, a := range arguments {
+		values = append(values, a.String())
+		isArgument[a.String()] = true
+	}
+
+	words, ok := rewrite(values)
+	if !ok {
+		return "", nil, false
+	}
+
+	// the new command and options are written in the case of the deprecated command
+	typed := statement.GetCommand().String()
+	for i, w := range words {
+		if !isArgument[w] {
+			words[i] = sameCase(typed, w)
+		}
+	}
+
+	replaced := Range{Start: nodeRange(statement.GetCommand()).Start, End: nodeRange(statement.GetCommand()).End}
+	if len(arguments) > 0 {
+		replaced.End = nodeRange(arguments[len(arguments)-1]).End
+	}
+
+	title := fmt.Sprintf("Replace %v with %v", command, strings.ToUpper(words[0]))
+
+	return title, []TextEdit{{Range: replaced, NewText: strings.Join(words, " ")}}, true
+}
+
+// transactionEnd returns the edit adding EXEC in a new line after the last command of the MULTI in the line,
+// which is the last one before the next MULTI or region marker.
+func transactionEnd(text string, statements []ast.TokenList, multiLine int) TextEdit {
+	line := multiLine
+	for _, s := range statements {
+		if s.Line() <= multiLine {
+			continue
+		}
+
+		command := s.GetCommand()
+		if command == nil {
+			if comment := ast.GetComment(s); comment != nil && ast.IsRegionMarker(comment.String()) {
+				break
+			}
+
+			continue
+		}
+
+		if ast.CommandName(s) == "MULTI" {
+			break
+		}
+
+		last := append([]ast.Node{command}, s.GetArguments()...)
+		line = ast.EndLine(last[len(last)-1])
+	}
+
+	lines := strings.Split(text, "\n")
+	end := Position{Line: line, Character: len(strings.TrimSuffix(lines[line], "\r"))}
+
+	newline := "\n"
+	if strings.Contains(text, "\r\n") {
+		newline = "\r\n"
+	}
+
+	return TextEdit{Range: Range{Start: end, End: end}, NewText: newline + "EXEC"}
+}
+
+// diagnosticNode returns the statement and its command or argument where the diagnostic starts.
+func diagnosticNode(statements []ast.TokenList, d analysis.Diagnostic) (ast.TokenList, ast.Node, bool) {
+	for _, s := range statements {
+		command := s.GetCommand()
+		if command == nil {
+			continue
+		}
+
+		for _, n := range append([]ast.Node{command}, s.GetArguments()...) {
+			if n.Line() == d.Line && n.LineStart() == d.Start {
+				return s, n, true
+			}
+		}
+	}
+
+	return nil, nil, false
+}
+
+// sameCase returns the value in lower case when the typed text is in lower case.
+func sameCase(typed string, value string) string {
+	if strings.ToLower(typed) == typed {
+		return strings.ToLower(value)
+	}
+
+	return value
+}
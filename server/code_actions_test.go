@@ -0,0 +1,77 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/fagnercarvalho/redis-lsp/ast"
+)
+
+func TestDeprecatedFix(t *testing.T) {
+	tests := []struct {
+		Name         string
+		Text         string
+		ExpectedText string
+	}{
+		{"Renamed command", "HMSET h f v", "HSET h f v"},
+		{"Seconds to option", "SETEX k 10 v", "SET k v EX 10"},
+		{"Milliseconds to option", "PSETEX k 100 v", "SET k v PX 100"},
+		{"Lower case", "setnx k v", "set k v nx"},
+		{"Pop and push", "RPOPLPUSH a b", "LMOVE a b RIGHT LEFT"},
+		{"Blocking pop and push", "BRPOPLPUSH a b 0", "BLMOVE a b RIGHT LEFT 0"},
+		{"Reverse range", "ZREVRANGE z 0 -1 WITHSCORES", "ZRANGE z 0 -1 REV WITHSCORES"},
+		{"Reverse range by score", "ZREVRANGEBYSCORE z +inf 0 LIMIT 0 10", "ZRANGE z +inf 0 BYSCORE REV LIMIT 0 10"},
+		{"Range by lex", "ZRANGEBYLEX z [a (b", "ZRANGE z [a (b BYLEX"},
+		{"Subcommand", "CLUSTER SLAVES id", "CLUSTER REPLICAS id"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			_, edits, ok := deprecatedFix(ast.Parse(test.Text)[0])
+			if !ok || len(edits) != 1 {
+				t.Fatalf("%v - Unexpected edits: %v (expected %v)", test.Name, edits, test.ExpectedText)
+			}
+
+			if edits[0].NewText != test.ExpectedText {
+				t.Errorf("%v - Unexpected text: %v (expected %v)", test.Name, edits[0].NewText, test.ExpectedText)
+			}
+		})
+	}
+}
+
+func TestDeprecatedFixWithoutArguments(t *testing.T) {
+	for _, text := range []string{"SETEX k 10", "ZREVRANGE z 0", "GET k"} {
+		if _, edits, ok := deprecatedFix(ast.Parse(text)[0]); ok {
+			t.Errorf("%v - Unexpected edits: %v (expected none)", text, edits)
+		}
+	}
+}
+
+func TestTransactionEnd(t *testing.T) {
+	tests := []struct {
+		Name             string
+		Text             string
+		MultiLine        int
+		ExpectedPosition Position
+		ExpectedText     string
+	}{
+		{"End of the document", "MULTI\nSET a 1\nINCR b\n", 0, Position{Line: 2, Character: 6}, "\nEXEC"},
+		{"Before the next MULTI", "MULTI\nSET a 1\nMULTI\nSET b 2\n", 0, Position{Line: 1, Character: 7}, "\nEXEC"},
+		{"Before the end of the region", "# region a\nMULTI\nSET a 1\n# endregion\nGET x\n", 1, Position{Line: 2, Character: 7}, "\nEXEC"},
+		{"Without commands", "GET x\nMULTI # start\n", 1, Position{Line: 1, Character: 13}, "\nEXEC"},
+		{"Comments are skipped", "MULTI\nSET a 1\n# done\n", 0, Position{Line: 1, Character: 7}, "\nEXEC"},
+		{"CRLF", "MULTI\r\nSET a 1\r\n", 0, Position{Line: 1, Character: 7}, "\r\nEXEC"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			edit := transactionEnd(test.Text, ast.Parse(test.Text), test.MultiLine)
+			if edit.Range.Start != test.ExpectedPosition || edit.Range.End != test.ExpectedPosition {
+				t.Errorf("%v - Unexpected range: %v (expected %v)", test.Name, edit.Range, test.ExpectedPosition)
+			}
+
+			if edit.NewText != test.ExpectedText {
+				t.Errorf("%v - Unexpected text: %q (expected %q)", test.Name, edit.NewText, test.ExpectedText)
+			}
+		})
+	}
+}
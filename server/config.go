@@ -0,0 +1,175 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/sourcegraph/jsonrpc2"
+	"gopkg.in/yaml.v3"
+)
+
+// configFiles are the names of the workspace configuration file, only the first one found in a root is used.
+var configFiles = []string{".redis-lsp.json", ".redis-lsp.yaml", ".redis-lsp.yml"}
+
+// readConfigFile returns the configuration file of the root as JSON, it is nil when the root has no configuration file.
+func readConfigFile(root string) (json.RawMessage, string, error) {
+	for _, name := range configFiles {
+		path := filepath.Join(root, name)
+		data, err := ioutil.ReadFile(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+
+		if err != nil {
+			return nil, path, err
+		}
+
+		if filepath.Ext(name) == ".json" {
+			if !json.Valid(data) {
+				return nil, path, fmt.Errorf("invalid JSON")
+			}
+
+			return data, path, nil
+		}
+
+		var config map[string]interface{}
+		err = yaml.Unmarshal(data, &config)
+		if err != nil {
+			return nil, path, err
+		}
+
+		raw, err := json.Marshal(config)
+		if err != nil {
+			return nil, path, err
+		}
+
+		return raw, path, nil
+	}
+
+	return nil, "", nil
+}
+
+// loadWorkspaceSettings reads the configuration files of the workspace roots and applies them.
+// Invalid files are reported to the user and ignored.
+func (s Server) loadWorkspaceSettings(ctx context.Context, conn *jsonrpc2.Conn) error {
+	var workspace []json.RawMessage
+	for _, root := range *s.roots {
+		raw, path, err := readConfigFile(root)
+		if err != nil {
+			message := ShowMessageParams{
+				Message: fmt.Sprintf("Error while reading %v: %v", path, err),
+				Type:    Error,
+			}
+
+			err = conn.Notify(ctx, "window/showMessage", message)
+			if err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if raw != nil {
+			log.Printf("using configuration file %v", path)
+			workspace = append(workspace, raw)
+		}
+	}
+
+	s.sources.workspace = workspace
+
+	err := s.reloadSettings()
+	if err != nil {
+		message := ShowMessageParams{
+			Message: fmt.Sprintf("Error while applying settings: %v", err),
+			Type:    Error,
+		}
+
+		return conn.Notify(ctx, "window/showMessage", message)
+	}
+
+	return nil
+}
+
+// watchConfigFiles asks the client to notify when a configuration file or a Lua file is created, changed or deleted.
+// It must not be called from a handler since the client response would only be read after the handler returns.
+func (s Server) watchConfigFiles(conn *jsonrpc2.Conn) {
+	var watchers []FileSystemWatcher
+	for _, name := range configFiles {
+		watchers = append(watchers, FileSystemWatcher{GlobPattern: "**/" + name})
+	}
+
+	watchers = append(watchers, FileSystemWatcher{GlobPattern: "**/*" + luaExtension})
+
+	params := RegistrationParams{
+		Registrations: []Registration{
+			{
+				Id:              "redis-lsp-config",
+				Method:          "workspace/didChangeWatchedFiles",
+				RegisterOptions: DidChangeWatchedFilesRegistrationOptions{Watchers: watchers},
+			},
+		},
+	}
+
+	err := conn.Call(context.Background(), "client/registerCapability", params, nil)
+	if err != nil {
+		log.Printf("error while watching the configuration files: %v", err)
+	}
+}
+
+func (s Server) handleDidChangeWatchedFiles(ctx context.Context, params *json.RawMessage, conn *jsonrpc2.Conn) (interface{}, error) {
+	var request DidChangeWatchedFilesParams
+	err := json.Unmarshal(*params, &request)
+	if err != nil {
+		return nil, err
+	}
+
+	changed := false
+	for _, c := range request.Changes {
+		// libraries may have been changed outside of the editor
+		if isScript(c.Uri) {
+			s.clearFunctions()
+		}
+
+		for _, name := range configFiles {
+			if filepath.Base(uriToPath(c.Uri)) == name {
+				changed = true
+			}
+		}
+	}
+
+	if !changed {
+		return nil, nil
+	}
+
+	err = s.loadWorkspaceSettings(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, s.settingsChanged(ctx, conn)
+}
+
+// settingsChanged updates the clients after the settings were changed
+// since the diagnostics and the connection of the documents may be different.
+func (s Server) settingsChanged(ctx context.Context, conn *jsonrpc2.Conn) error {
+	s.clearFunctions()
+
+	for uri := range s.files {
+		err := s.publishDiagnostics(ctx, conn, uri)
+		if err != nil {
+			return err
+		}
+
+		err = s.notifyConnection(ctx, conn, uri)
+		if err != nil {
+			return err
+		}
+	}
+
+	return s.notifyConnection(ctx, conn, "")
+}
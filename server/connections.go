@@ -0,0 +1,181 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/fagnercarvalho/redis-lsp/ast"
+	"github.com/fagnercarvalho/redis-lsp/client"
+	"github.com/sourcegraph/jsonrpc2"
+)
+
+const (
+	switchConnectionCommand = "redis.switchConnection"
+
+	// connectionDirective is used in a comment in the beginning of a document
+	// to choose the connection for its commands (e.g. "# @connection staging").
+	connectionDirective = "@connection"
+
+	// defaultConnection is the name of the connection created from the command line flags.
+	defaultConnection = "default"
+)
+
+// documentConnection returns the connection chosen in the header of the document and the comment where it was chosen.
+// The header is every comment and empty line before the first command.
+func documentConnection(text string) (string, ast.Node) {
+	for _, s := range ast.Parse(text) {
+		if s.GetCommand() != nil {
+			break
+		}
+
+		comment := ast.GetComment(s)
+		if comment == nil {
+			continue
+		}
+
+		fields := strings.Fields(ast.CommentText(comment.String()))
+		if len(fields) == 2 && fields[0] == connectionDirective {
+			return fields[1], comment
+		}
+	}
+
+	return "", nil
+}
+
+// connection returns the Redis connection for the document, an empty URI means the active connection.
+func (s Server) connection(uri string) (client.Redis, error) {
+	name, _ := documentConnection(s.files[uri])
+	return s.connections.Client(name)
+}
+
+// showError shows the message of a command that failed.
+func showError(ctx context.Context, conn *jsonrpc2.Conn, message string) (interface{}, error) {
+	return nil, conn.Notify(ctx, "window/showMessage", ShowMessageParams{Message: message, Type: Error})
+}
+
+// blockedInSafeMode reports whether the command is dangerous in safe mode and tells the user it is not executed.
+func (s Server) blockedInSafeMode(ctx context.Context, conn *jsonrpc2.Conn, command []interface{}) (bool, error) {
+	dangerous, ok := s.settings.Safety.blocked(command)
+	if !ok {
+		return false, nil
+	}
+
+	message := ShowMessageParams{Message: fmt.Sprintf("%v is not executed in safe mode", dangerous), Type: Warning}
+
+	return true, conn.Notify(ctx, "window/showMessage", message)
+}
+
+// executeFor runs the command on the connection of the document, ok is false when it is blocked in safe mode
+// or it fails, which is already shown to the user.
+func (s Server) executeFor(ctx context.Context, conn *jsonrpc2.Conn, uri string, command []interface{}) (interface{}, bool, error) {
+	if blocked, err := s.blockedInSafeMode(ctx, conn, command); blocked {
+		return nil, false, err
+	}
+
+	redis, err := s.connection(uri)
+	if err != nil {
+		_, err = showError(ctx, conn, err.Error())
+		return nil, false, err
+	}
+
+	val, err := redis.ExecuteCommand(ctx, command)
+	if err != nil {
+		_, err = showError(ctx, conn, err.Error())
+		return nil, false, err
+	}
+
+	return val, true, nil
+}
+
+// connectionProfile returns the profile used by the document. A connection that is not defined
+// only has its name, like connection it is not replaced by the active connection.
+func (s Server) connectionProfile(uri string) client.Profile {
+	name, _ := documentConnection(s.files[uri])
+	if name == "" {
+		return s.connections.Active()
+	}
+
+	if p, ok := s.connections.Profile(name); ok {
+		return p
+	}
+
+	return client.Profile{Name: name}
+}
+
+// notifyConnection tells the client where the commands of the document are sent to
+// so it can be shown to the user (e.g. in the status bar).
+func (s Server) notifyConnection(ctx context.Context, conn *jsonrpc2.Conn, uri string) error {
+	profile := s.connectionProfile(uri)
+	s.documentConnections[uri] = profile.Name
+
+	status := ConnectionStatusParams{
+		Uri:        uri,
+		Connection: profile.Name,
+		Address:    profile.Address,
+		Database:   profile.Database,
+	}
+
+	if _, ok := s.connections.Profile(profile.Name); !ok {
+		status.Error = fmt.Sprintf("connection %v is not defined", profile.Name)
+	}
+
+	return conn.Notify(ctx, "redis/connectionStatus", status)
+}
+
+// switchConnection changes the connection used by documents that do not choose one.
+// Without arguments it returns the names of the connections so the client can ask the user which one to use.
+func (s Server) switchConnection(ctx context.Context, arguments []interface{}, conn *jsonrpc2.Conn) (interface{}, error) {
+	if len(arguments) == 0 {
+		var names []string
+		for _, p := range s.connections.Profiles() {
+			names = append(names, p.Name)
+		}
+
+		return names, nil
+	}
+
+	name, _ := arguments[0].(string)
+	err := s.connections.Switch(name)
+	if err != nil {
+		return nil, conn.Notify(ctx, "window/showMessage", ShowMessageParams{Message: err.Error(), Type: Error})
+	}
+
+	profile := s.connections.Active()
+	message := ShowMessageParams{
+		Message: fmt.Sprintf("Commands are now sent to %v (%v, database %v)", profile.Name, profile.Address, profile.Database),
+		Type:    Info,
+	}
+
+	err = conn.Notify(ctx, "window/showMessage", message)
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, s.notifyConnection(ctx, conn, "")
+}
+
+// targetVersion returns the Redis version the document is written for, which is the targetVersion setting
+// or, when it is not set, the version of the server the document is connected to.
+// It is empty when the version is unknown so every command is available.
+func (s Server) targetVersion(uri string) string {
+	if s.settings.TargetVersion != "" {
+		return s.settings.TargetVersion
+	}
+
+	redis, err := s.connection(uri)
+	if err != nil {
+		return ""
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), hoverTimeout)
+	defer cancel()
+
+	version, err := redis.Version(ctx)
+	if err != nil {
+		log.Printf("error while getting the Redis version: %v", err)
+	}
+
+	return version
+}
@@ -0,0 +1,142 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/fagnercarvalho/redis-lsp/analysis"
+	"github.com/fagnercarvalho/redis-lsp/ast"
+	"github.com/go-redis/redis/v8"
+	"github.com/sourcegraph/jsonrpc2"
+)
+
+// publishDiagnostics analyzes the document and sends every problem found to the client.
+func (s Server) publishDiagnostics(ctx context.Context, conn *jsonrpc2.Conn, uri string) error {
+	found := s.analyze(uri)
+
+	diagnostics := []Diagnostic{}
+	for _, d := range found {
+		if !s.settings.Diagnostics.diagnosticEnabled(d.Code) {
+			continue
+		}
+
+		diagnostics = append(diagnostics, lspDiagnostic(d))
+	}
+
+	return conn.Notify(ctx, "textDocument/publishDiagnostics", PublishDiagnosticsParams{
+		Uri:         uri,
+		Version:     s.versions[uri],
+		Diagnostics: diagnostics,
+	})
+}
+
+// analyze returns the problems found in the document, Lua script files only have the script problems.
+func (s Server) analyze(uri string) []analysis.Diagnostic {
+	if isScript(uri) {
+		return analysis.ScriptFile(s.files[uri])
+	}
+
+	statements := ast.Parse(s.files[uri])
+
+	var found []analysis.Diagnostic
+	found = append(found, analysis.Tokens(statements)...)
+	found = append(found, analysis.UnknownCommands(statements)...)
+	found = append(found, analysis.UnquotedSpaces(statements)...)
+	found = append(found, analysis.UnclosedTransactions(statements)...)
+	found = append(found, analysis.Arguments(statements)...)
+	found = append(found, analysis.Scripts(statements)...)
+	found = append(found, analysis.Numkeys(statements)...)
+	found = append(found, analysis.Variables(statements, s.variableResolver("", statements))...)
+	found = append(found, analysis.Versions(statements, s.targetVersion(uri))...)
+	found = append(found, s.lint(uri, statements)...)
+
+	if name, comment := documentConnection(s.files[uri]); name != "" {
+		if _, ok := s.connections.Profile(name); !ok {
+			message := fmt.Sprintf("connection %v is not defined", name)
+			found = append(found, analysis.NewDiagnostic(comment.Line(), comment, analysis.Error, "unknown-connection", message))
+		}
+	}
+
+	return found
+}
+
+// lint returns the lint warnings with the severity configured for each rule.
+func (s Server) lint(uri string, statements []ast.TokenList) []analysis.Diagnostic {
+	var cardinality analysis.Cardinality
+	if s.settings.Lint.LiveCardinality {
+		cardinality = s.cardinality(uri)
+	}
+
+	var found []analysis.Diagnostic
+	for _, d := range analysis.Lint(statements, cardinality, s.settings.Lint.LargeKey) {
+		severity, ok := s.settings.Lint.severity(d.Code)
+		if !ok {
+			continue
+		}
+
+		d.Severity = severity
+		found = append(found, d)
+	}
+
+	return found
+}
+
+// cardinality returns the function fetching the number of elements of the keys from the connection of the document,
+// each key is only fetched once and nothing else is fetched after the server cannot be reached,
+// so a server that is down does not make every key wait for the timeout.
+func (s Server) cardinality(uri string) analysis.Cardinality {
+	fetched := map[string]int64{}
+	failed := map[string]bool{}
+	unreachable := false
+
+	return func(key string) (int64, bool) {
+		if n, ok := fetched[key]; ok {
+			return n, true
+		}
+
+		if unreachable || failed[key] {
+			return 0, false
+		}
+
+		redisClient, err := s.connection(uri)
+		if err != nil {
+			unreachable = true
+			return 0, false
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), hoverTimeout)
+		defer cancel()
+
+		n, err := redisClient.GetCardinality(ctx, key)
+		if err != nil {
+			log.Printf("error while getting the cardinality of key %v: %v", key, err)
+
+			// errors replied by the server only concern the key
+			if _, ok := err.(redis.Error); !ok {
+				unreachable = true
+			}
+
+			failed[key] = true
+
+			return 0, false
+		}
+
+		fetched[key] = n
+
+		return n, true
+	}
+}
+
+func lspDiagnostic(d analysis.Diagnostic) Diagnostic {
+	return Diagnostic{
+		Range: Range{
+			Start: Position{Line: d.Line, Character: d.Start},
+			End:   Position{Line: d.Line, Character: d.End},
+		},
+		Severity: DiagnosticSeverity(d.Severity),
+		Code:     d.Code,
+		Source:   "redis-lsp",
+		Message:  d.Message,
+	}
+}
@@ -0,0 +1,80 @@
+package server
+
+import (
+	"encoding/json"
+
+	"github.com/fagnercarvalho/redis-lsp/ast"
+	"github.com/fagnercarvalho/redis-lsp/token"
+)
+
+// handleFoldingRange folds transactions, strings with line breaks (e.g. EVAL scripts),
+// consecutive comment lines and regions.
+func (s Server) handleFoldingRange(params *json.RawMessage) (interface{}, error) {
+	var request FoldingRangeParams
+	err := json.Unmarshal(*params, &request)
+	if err != nil {
+		return nil, err
+	}
+
+	if isScript(request.TextDocument.Uri) {
+		return nil, nil
+	}
+
+	statements := ast.Parse(s.files[request.TextDocument.Uri])
+
+	ranges := []FoldingRange{}
+	for _, t := range ast.GetTransactions(statements) {
+		if t.EndLine > t.StartLine {
+			ranges = append(ranges, FoldingRange{StartLine: t.StartLine, EndLine: t.EndLine})
+		}
+	}
+
+	for _, r := range ast.GetRegions(statements) {
+		if r.EndLine > r.StartLine {
+			ranges = append(ranges, FoldingRange{StartLine: r.StartLine, EndLine: r.EndLine, Kind: RegionFolding})
+		}
+	}
+
+	for _, statement := range statements {
+		for _, t := range statement.GetArguments() {
+			if t.Type() == token.String && ast.EndLine(t) > t.Line() {
+				ranges = append(ranges, FoldingRange{StartLine: t.Line(), EndLine: ast.EndLine(t)})
+			}
+		}
+	}
+
+	return append(ranges, commentFoldingRanges(statements)...), nil
+}
+
+// commentFoldingRanges returns a range for each block of two or more consecutive comment lines,
+// region comments are not part of the blocks since they are folded with their regions.
+func commentFoldingRanges(statements []ast.TokenList) []FoldingRange {
+	var ranges []FoldingRange
+	start, end := -1, -1
+	flush := func() {
+		if start != -1 && end > start {
+			ranges = append(ranges, FoldingRange{StartLine: start, EndLine: end, Kind: CommentFolding})
+		}
+
+		start, end = -1, -1
+	}
+
+	for _, statement := range statements {
+		comment := ast.GetComment(statement)
+		if comment == nil || statement.GetCommand() != nil || ast.IsRegionMarker(comment.String()) {
+			flush()
+			continue
+		}
+
+		if statement.Line() != end+1 || start == -1 {
+			flush()
+			start = statement.Line()
+		}
+
+		end = statement.Line()
+	}
+
+	flush()
+
+	return ranges
+}
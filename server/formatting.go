@@ -0,0 +1,96 @@
+package server
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/fagnercarvalho/redis-lsp/format"
+)
+
+func (s Server) handleFormatting(params *json.RawMessage) (interface{}, error) {
+	var request DocumentFormattingParams
+	err := json.Unmarshal(*params, &request)
+	if err != nil {
+		return nil, err
+	}
+
+	if isScript(request.TextDocument.Uri) {
+		return nil, nil
+	}
+
+	text := s.files[request.TextDocument.Uri]
+	lines := strings.Split(text, "\n")
+
+	return formattingEdits(text, 0, len(lines)-1, format.Format(text, s.formatOptions(request.Options))), nil
+}
+
+func (s Server) handleRangeFormatting(params *json.RawMessage) (interface{}, error) {
+	var request DocumentRangeFormattingParams
+	err := json.Unmarshal(*params, &request)
+	if err != nil {
+		return nil, err
+	}
+
+	if isScript(request.TextDocument.Uri) {
+		return nil, nil
+	}
+
+	text := s.files[request.TextDocument.Uri]
+
+	// only whole lines are formatted, a range ending at the start of a line does not include it
+	start, end := request.Range.Start.Line, request.Range.End.Line
+	if end > start && request.Range.End.Character == 0 {
+		end--
+	}
+
+	return formattingEdits(text, start, end, format.Range(text, start, end, s.formatOptions(request.Options))), nil
+}
+
+// formatOptions combines the formatting settings with the options of the editor.
+func (s Server) formatOptions(options FormattingOptions) format.Options {
+	indent := "\t"
+	if options.InsertSpaces {
+		size := options.TabSize
+		if size <= 0 {
+			size = 4
+		}
+
+		indent = strings.Repeat(" ", size)
+	}
+
+	return format.Options{Case: s.settings.Formatting.Case, Indent: indent}
+}
+
+// formattingEdits returns an edit replacing the lines from start to end (inclusive) with the formatted text,
+// there are no edits when the text is already formatted.
+func formattingEdits(text string, start int, end int, formatted string) []TextEdit {
+	lines := strings.Split(text, "\n")
+	if start < 0 || start >= len(lines) {
+		return []TextEdit{}
+	}
+
+	if end >= len(lines) {
+		end = len(lines) - 1
+	}
+
+	lastLine := strings.TrimSuffix(lines[end], "\r")
+	original := strings.Join(lines[start:end], "\n")
+	if end > start {
+		original += "\n"
+	}
+	original += lastLine
+
+	if original == formatted {
+		return []TextEdit{}
+	}
+
+	return []TextEdit{
+		{
+			Range: Range{
+				Start: Position{Line: start, Character: 0},
+				End:   Position{Line: end, Character: len(lastLine)},
+			},
+			NewText: formatted,
+		},
+	}
+}
@@ -0,0 +1,151 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"path/filepath"
+	"time"
+
+	"github.com/fagnercarvalho/redis-lsp/lua"
+	"github.com/sourcegraph/jsonrpc2"
+)
+
+const loadFunctionCommand = "redis.loadFunction"
+
+// libraryFunction is a function registered by a library file of the workspace.
+type libraryFunction struct {
+	Library  string
+	Location Location
+}
+
+// workspaceFunctions returns the functions registered by the library files of the workspace by name.
+func (s Server) workspaceFunctions() map[string][]libraryFunction {
+	functions := map[string][]libraryFunction{}
+
+	scripts := s.workspaceDocuments(luaExtension)
+	for _, uri := range sortedURIs(scripts) {
+		text := scripts[uri]
+		library, ok := lua.ParseLibrary(text)
+		if !ok {
+			continue
+		}
+
+		for _, f := range library.Functions {
+			functions[f.Name] = append(functions[f.Name], libraryFunction{
+				Library:  library.Name,
+				Location: Location{Uri: uri, Range: Range{Start: textPosition(text, f.Start), End: textPosition(text, f.End)}},
+			})
+		}
+	}
+
+	return functions
+}
+
+// functionsTTL is how long the functions of a connection are kept, since another client can load or delete libraries.
+const functionsTTL = time.Minute
+
+// functionsRetry is how long the functions are kept when FUNCTION LIST failed before it is sent again.
+const functionsRetry = 10 * time.Second
+
+// functionCache holds the functions of a connection so completion does not walk the workspace
+// and send FUNCTION LIST every time.
+type functionCache struct {
+	names     map[string]string
+	expiresAt time.Time
+}
+
+// functionNames returns the functions that can be called with FCALL with the library of each one,
+// from the library files of the workspace and, when caching is enabled, from FUNCTION LIST.
+// They are kept for the connection for functionsTTL or until a library changes (see clearFunctions).
+func (s Server) functionNames(uri string) map[string]string {
+	connection := s.connectionProfile(uri).Name
+	if cache, ok := s.functions[connection]; ok && time.Now().Before(cache.expiresAt) {
+		return cache.names
+	}
+
+	names, err := s.fetchFunctionNames(uri)
+
+	ttl := functionsTTL
+	if err != nil {
+		log.Printf("error while getting functions: %v", err)
+		ttl = functionsRetry
+	}
+
+	s.functions[connection] = &functionCache{names: names, expiresAt: time.Now().Add(ttl)}
+
+	return names
+}
+
+// fetchFunctionNames returns the functions of the library files of the workspace and of FUNCTION LIST,
+// the functions of the workspace are returned even when FUNCTION LIST fails.
+func (s Server) fetchFunctionNames(uri string) (map[string]string, error) {
+	names := map[string]string{}
+	for name, functions := range s.workspaceFunctions() {
+		names[name] = functions[0].Library
+	}
+
+	if !s.connections.CacheEnabled() {
+		return names, nil
+	}
+
+	redis, err := s.connection(uri)
+	if err != nil {
+		return names, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), hoverTimeout)
+	defer cancel()
+
+	loaded, err := redis.GetFunctions(ctx)
+	if err != nil {
+		return names, err
+	}
+
+	for name, library := range loaded {
+		names[name] = library
+	}
+
+	return names, nil
+}
+
+// clearFunctions removes the functions kept by functionNames so they are fetched again.
+func (s Server) clearFunctions() {
+	for connection := range s.functions {
+		delete(s.functions, connection)
+	}
+}
+
+// loadFunction loads a library file with FUNCTION LOAD REPLACE so the functions are replaced when the library was already loaded.
+func (s Server) loadFunction(ctx context.Context, arguments []interface{}, conn *jsonrpc2.Conn) (interface{}, error) {
+	uri, text, err := s.scriptFile(arguments)
+	if err != nil {
+		return showError(ctx, conn, err.Error())
+	}
+
+	if _, ok := lua.ParseLibrary(text); !ok {
+		return showError(ctx, conn, fmt.Sprintf("%v does not start with a #!lua name=<library> line", filepath.Base(uriToPath(uri))))
+	}
+
+	command := []interface{}{"FUNCTION", "LOAD", "REPLACE", text}
+	val, ok, err := s.executeFor(ctx, conn, uri, command)
+	if !ok {
+		return nil, err
+	}
+
+	s.clearFunctions()
+
+	message := ShowMessageParams{Message: fmt.Sprintf("%v loaded as library %v", filepath.Base(uriToPath(uri)), val), Type: Info}
+
+	return val, conn.Notify(ctx, "window/showMessage", message)
+}
+
+// functionDefinitions returns where the function is registered in the library files of the workspace.
+func (s Server) functionDefinitions(name string) []Location {
+	locations := []Location{}
+	for _, f := range s.workspaceFunctions()[name] {
+		locations = append(locations, f.Location)
+	}
+
+	return locations
+}
@@ -0,0 +1,160 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/fagnercarvalho/redis-lsp/ast"
+	"github.com/fagnercarvalho/redis-lsp/client"
+)
+
+// hoverTimeout is how long we wait for Redis before giving up on showing the key information.
+const hoverTimeout = 500 * time.Millisecond
+
+// keyInfoCache holds the key information fetched for a given document version
+// so hovering the same key multiple times does not hit Redis every time.
+type keyInfoCache struct {
+	version    int
+	connection string
+	keys       map[string]client.KeyInfo
+}
+
+func (s Server) handleHover(ctx context.Context, params *json.RawMessage) (interface{}, error) {
+	var request HoverParams
+	err := json.Unmarshal(*params, &request)
+	if err != nil {
+		return nil, err
+	}
+
+	uri := request.TextDocument.Uri
+	if isScript(uri) {
+		if hover := scriptFileHover(s.files[uri], request.Position.Line, request.Position.Character); hover != nil {
+			return hover, nil
+		}
+
+		return nil, nil
+	}
+
+	statements := ast.Parse(s.files[uri])
+
+	statement, selected := ast.GetSelectedToken(statements, request.Position.Line, request.Position.Character)
+	if selected == nil {
+		return nil, nil
+	}
+
+	hover := variableHover(s.variableResolver("", statements), request.Position.Line, request.Position.Character, selected)
+	if hover != nil {
+		return hover, nil
+	}
+
+	hover = scriptIndexHover(statement, selected, request.Position.Line, request.Position.Character)
+	if hover != nil {
+		return hover, nil
+	}
+
+	// the keys and arguments of EVAL show the KEYS[n] or ARGV[n] they are in the script
+	argument, isArgument := scriptArgument(statement, selected)
+	selectedRange := &Range{
+		Start: Position{Line: request.Position.Line, Character: selected.LineStart()},
+		End:   Position{Line: request.Position.Line, Character: selected.LineEnd() + 1},
+	}
+
+	key, ok := selectedKey(statement, selected)
+	if !ok {
+		if isArgument {
+			value := fmt.Sprintf("### %v \n Argument passed to the script.", argument)
+			return Hover{Contents: MarkupContent{Kind: Markdown, Value: value}, Range: selectedRange}, nil
+		}
+
+		return nil, nil
+	}
+
+	title := key
+	if isArgument {
+		title = fmt.Sprintf("%v (%v)", key, argument)
+	}
+
+	info, err := s.getKeyInfo(ctx, uri, key)
+	if err != nil {
+		// Redis may be unavailable or slow, there is nothing to show in this case
+		log.Printf("error while getting information for key %v: %v", key, err)
+		if isArgument {
+			value := fmt.Sprintf("### %v \n Key passed to the script.", argument)
+			return Hover{Contents: MarkupContent{Kind: Markdown, Value: value}, Range: selectedRange}, nil
+		}
+
+		return nil, nil
+	}
+
+	return Hover{
+		Contents: MarkupContent{Kind: Markdown, Value: formatKeyInfo(title, info)},
+		Range:    selectedRange,
+	}, nil
+}
+
+func (s Server) getKeyInfo(ctx context.Context, uri string, key string) (client.KeyInfo, error) {
+	connection := s.connectionProfile(uri).Name
+	cache, ok := s.keyInfo[uri]
+	if !ok || cache.version != s.versions[uri] || cache.connection != connection {
+		cache = &keyInfoCache{version: s.versions[uri], connection: connection, keys: map[string]client.KeyInfo{}}
+		s.keyInfo[uri] = cache
+	}
+
+	info, ok := cache.keys[key]
+	if ok {
+		return info, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, hoverTimeout)
+	defer cancel()
+
+	redis, err := s.connection(uri)
+	if err != nil {
+		return client.KeyInfo{}, err
+	}
+
+	info, err = redis.GetKeyInfo(ctx, key)
+	if err != nil {
+		return info, err
+	}
+
+	cache.keys[key] = info
+
+	return info, nil
+}
+
+func formatKeyInfo(key string, info client.KeyInfo) string {
+	if !info.Exists {
+		return fmt.Sprintf("### %v \n Key does not exist.", key)
+	}
+
+	ttl := "no expiration"
+	if info.TTL >= 0 {
+		ttl = fmt.Sprintf("%v (%v ms)", info.TTL, info.PTTL.Milliseconds())
+	}
+
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("### %v \n", key))
+	builder.WriteString("| | |\n|---|---|\n")
+	builder.WriteString(fmt.Sprintf("| Type | %v |\n", info.Type))
+	builder.WriteString(fmt.Sprintf("| TTL | %v |\n", ttl))
+	if info.Memory > 0 {
+		builder.WriteString(fmt.Sprintf("| Memory | %v bytes |\n", info.Memory))
+	}
+
+	if info.Encoding != "" {
+		builder.WriteString(fmt.Sprintf("| Encoding | %v |\n", info.Encoding))
+	}
+
+	builder.WriteString(fmt.Sprintf("| Length | %v |\n", info.Length))
+
+	if info.Preview != "" {
+		builder.WriteString(fmt.Sprintf("\n```\n%v\n```\n", info.Preview))
+	}
+
+	return builder.String()
+}
@@ -1,7 +1,48 @@
 package server
 
+import (
+	"encoding/json"
+)
+
 // initialize
 
+type InitializeParams struct {
+	RootUri               string             `json:"rootUri"`
+	WorkspaceFolders      []WorkspaceFolder  `json:"workspaceFolders"`
+	Capabilities          ClientCapabilities `json:"capabilities"`
+	InitializationOptions json.RawMessage    `json:"initializationOptions"`
+}
+
+type WorkspaceFolder struct {
+	Uri  string `json:"uri"`
+	Name string `json:"name"`
+}
+
+type ClientCapabilities struct {
+	TextDocument TextDocumentClientCapabilities `json:"textDocument"`
+	Workspace    WorkspaceClientCapabilities    `json:"workspace"`
+}
+
+type WorkspaceClientCapabilities struct {
+	DidChangeWatchedFiles DynamicRegistrationCapabilities `json:"didChangeWatchedFiles"`
+}
+
+type DynamicRegistrationCapabilities struct {
+	DynamicRegistration bool `json:"dynamicRegistration"`
+}
+
+type TextDocumentClientCapabilities struct {
+	Completion CompletionClientCapabilities `json:"completion"`
+}
+
+type CompletionClientCapabilities struct {
+	CompletionItem CompletionItemCapabilities `json:"completionItem"`
+}
+
+type CompletionItemCapabilities struct {
+	SnippetSupport bool `json:"snippetSupport"`
+}
+
 type InitializeResult struct {
 	Capabilities Capabilities `json:"capabilities"`
 }
@@ -27,16 +68,45 @@ type Capabilities struct {
 	ExecuteCommandProvider ExecuteCommandOptions `json:"executeCommandProvider"`
 	HoverProvider          bool                  `json:"hoverProvider"`
 	SelectionRangeProvider bool                  `json:"selectionRangeProvider"`
+
+	DocumentSymbolProvider          bool               `json:"documentSymbolProvider"`
+	FoldingRangeProvider            bool               `json:"foldingRangeProvider"`
+	ReferencesProvider              bool               `json:"referencesProvider"`
+	DocumentHighlightProvider       bool               `json:"documentHighlightProvider"`
+	RenameProvider                  RenameOptions      `json:"renameProvider"`
+	WorkspaceSymbolProvider         bool               `json:"workspaceSymbolProvider"`
+	DocumentFormattingProvider      bool               `json:"documentFormattingProvider"`
+	DocumentRangeFormattingProvider bool               `json:"documentRangeFormattingProvider"`
+	DefinitionProvider              bool               `json:"definitionProvider"`
+	CodeLensProvider                *CodeLensOptions   `json:"codeLensProvider,omitempty"`
+	CodeActionProvider              *CodeActionOptions `json:"codeActionProvider,omitempty"`
 }
 
 // completion
 
 type CompletionItem struct {
-	Label         string             `json:"label"`
-	Kind          CompletionItemKind `json:"kind"`
-	Documentation interface{}        `json:"documentation,omitempty"`
+	Label            string             `json:"label"`
+	Kind             CompletionItemKind `json:"kind"`
+	Detail           string             `json:"detail,omitempty"`
+	Documentation    interface{}        `json:"documentation,omitempty"`
+	SortText         string             `json:"sortText,omitempty"`
+	FilterText       string             `json:"filterText,omitempty"`
+	InsertTextFormat InsertTextFormat   `json:"insertTextFormat,omitempty"`
+	TextEdit         *TextEdit          `json:"textEdit,omitempty"`
 }
 
+type TextEdit struct {
+	Range   Range  `json:"range"`
+	NewText string `json:"newText"`
+}
+
+type InsertTextFormat int
+
+const (
+	PlainTextFormat InsertTextFormat = 1
+	SnippetFormat   InsertTextFormat = 2
+)
+
 type MarkupContent struct {
 	Kind  MarkupKind `json:"kind"`
 	Value string     `json:"value"`
@@ -52,8 +122,14 @@ const (
 type CompletionItemKind int
 
 const (
-	Text    = 1
-	Keyword = 14
+	Text     = 1
+	Function = 3
+	Field    = 5
+	Variable = 6
+	Value    = 12
+	Enum     = 13
+	Keyword  = 14
+	Constant = 21
 )
 
 type CompletionParams struct {
@@ -74,6 +150,211 @@ type Position struct {
 	Character int `json:"character"`
 }
 
+type Range struct {
+	Start Position `json:"start"`
+	End   Position `json:"end"`
+}
+
+// hover
+
+type HoverParams struct {
+	TextDocumentPositionParams
+}
+
+type Hover struct {
+	Contents MarkupContent `json:"contents"`
+	Range    *Range        `json:"range,omitempty"`
+}
+
+// formatting
+
+type DocumentFormattingParams struct {
+	TextDocument TextDocumentIdentifier `json:"textDocument"`
+	Options      FormattingOptions      `json:"options"`
+}
+
+type DocumentRangeFormattingParams struct {
+	TextDocument TextDocumentIdentifier `json:"textDocument"`
+	Range        Range                  `json:"range"`
+	Options      FormattingOptions      `json:"options"`
+}
+
+type FormattingOptions struct {
+	TabSize      int  `json:"tabSize"`
+	InsertSpaces bool `json:"insertSpaces"`
+}
+
+// documentSymbol
+
+type DocumentSymbolParams struct {
+	TextDocument TextDocumentIdentifier `json:"textDocument"`
+}
+
+type DocumentSymbol struct {
+	Name           string           `json:"name"`
+	Detail         string           `json:"detail,omitempty"`
+	Kind           SymbolKind       `json:"kind"`
+	Range          Range            `json:"range"`
+	SelectionRange Range            `json:"selectionRange"`
+	Children       []DocumentSymbol `json:"children,omitempty"`
+}
+
+type SymbolKind int
+
+const (
+	NamespaceSymbol SymbolKind = 3
+	FunctionSymbol  SymbolKind = 12
+	VariableSymbol  SymbolKind = 13
+	ObjectSymbol    SymbolKind = 19
+	KeySymbol       SymbolKind = 20
+)
+
+// selectionRange
+
+type SelectionRangeParams struct {
+	TextDocument TextDocumentIdentifier `json:"textDocument"`
+	Positions    []Position             `json:"positions"`
+}
+
+type SelectionRange struct {
+	Range  Range           `json:"range"`
+	Parent *SelectionRange `json:"parent,omitempty"`
+}
+
+// foldingRange
+
+type FoldingRangeParams struct {
+	TextDocument TextDocumentIdentifier `json:"textDocument"`
+}
+
+type FoldingRange struct {
+	StartLine int              `json:"startLine"`
+	EndLine   int              `json:"endLine"`
+	Kind      FoldingRangeKind `json:"kind,omitempty"`
+}
+
+type FoldingRangeKind string
+
+const (
+	CommentFolding FoldingRangeKind = "comment"
+	RegionFolding  FoldingRangeKind = "region"
+)
+
+// references
+
+type ReferenceParams struct {
+	TextDocument TextDocumentIdentifier `json:"textDocument"`
+	Position     Position               `json:"position"`
+}
+
+type Location struct {
+	Uri   string `json:"uri"`
+	Range Range  `json:"range"`
+}
+
+// rename
+
+type RenameOptions struct {
+	PrepareProvider bool `json:"prepareProvider"`
+}
+
+type RenameParams struct {
+	TextDocument TextDocumentIdentifier `json:"textDocument"`
+	Position     Position               `json:"position"`
+	NewName      string                 `json:"newName"`
+}
+
+type PrepareRenameResult struct {
+	Range       Range  `json:"range"`
+	Placeholder string `json:"placeholder"`
+}
+
+type WorkspaceEdit struct {
+	Changes map[string][]TextEdit `json:"changes"`
+}
+
+// documentHighlight
+
+type DocumentHighlight struct {
+	Range Range                 `json:"range"`
+	Kind  DocumentHighlightKind `json:"kind"`
+}
+
+type DocumentHighlightKind int
+
+const (
+	TextHighlight  DocumentHighlightKind = 1
+	ReadHighlight  DocumentHighlightKind = 2
+	WriteHighlight DocumentHighlightKind = 3
+)
+
+// workspace/symbol
+
+type WorkspaceSymbolParams struct {
+	Query string `json:"query"`
+}
+
+type SymbolInformation struct {
+	Name          string     `json:"name"`
+	Kind          SymbolKind `json:"kind"`
+	Location      Location   `json:"location"`
+	ContainerName string     `json:"containerName,omitempty"`
+}
+
+// codeLens
+
+type CodeLensOptions struct {
+	ResolveProvider bool `json:"resolveProvider"`
+}
+
+type CodeLensParams struct {
+	TextDocument TextDocumentIdentifier `json:"textDocument"`
+}
+
+type CodeLens struct {
+	Range   Range    `json:"range"`
+	Command *Command `json:"command,omitempty"`
+}
+
+type Command struct {
+	Title     string        `json:"title"`
+	Command   string        `json:"command"`
+	Arguments []interface{} `json:"arguments,omitempty"`
+}
+
+// codeAction
+
+type CodeActionKind string
+
+const (
+	QuickFix        CodeActionKind = "quickfix"
+	RefactorExtract CodeActionKind = "refactor.extract"
+	RefactorRewrite CodeActionKind = "refactor.rewrite"
+)
+
+type CodeActionOptions struct {
+	CodeActionKinds []CodeActionKind `json:"codeActionKinds"`
+}
+
+type CodeActionParams struct {
+	TextDocument TextDocumentIdentifier `json:"textDocument"`
+	Range        Range                  `json:"range"`
+	Context      CodeActionContext      `json:"context"`
+}
+
+type CodeActionContext struct {
+	Diagnostics []Diagnostic     `json:"diagnostics"`
+	Only        []CodeActionKind `json:"only,omitempty"`
+}
+
+type CodeAction struct {
+	Title       string         `json:"title"`
+	Kind        CodeActionKind `json:"kind"`
+	Diagnostics []Diagnostic   `json:"diagnostics,omitempty"`
+	IsPreferred bool           `json:"isPreferred,omitempty"`
+	Edit        *WorkspaceEdit `json:"edit,omitempty"`
+}
+
 // didOpen
 
 type DidOpenTextDocumentParams struct {
@@ -95,13 +376,32 @@ type DidChangeTextDocumentParams struct {
 }
 
 type VersionedTextDocumentIdentifier struct {
-	Uri string `json:"uri"`
+	Uri     string `json:"uri"`
+	Version int    `json:"version"`
 }
 
 type TextDocumentContentChangeEvent struct {
 	Text string `json:"text"`
 }
 
+// publishDiagnostics
+
+type PublishDiagnosticsParams struct {
+	Uri         string       `json:"uri"`
+	Version     int          `json:"version"`
+	Diagnostics []Diagnostic `json:"diagnostics"`
+}
+
+type Diagnostic struct {
+	Range    Range              `json:"range"`
+	Severity DiagnosticSeverity `json:"severity"`
+	Code     string             `json:"code,omitempty"`
+	Source   string             `json:"source"`
+	Message  string             `json:"message"`
+}
+
+type DiagnosticSeverity int
+
 // logMessage
 
 type LogMessageParams struct {
@@ -125,6 +425,16 @@ type ExecuteCommandParams struct {
 	Arguments []interface{} `json:"arguments"`
 }
 
+// redis/connectionStatus
+
+type ConnectionStatusParams struct {
+	Uri        string `json:"uri,omitempty"`
+	Connection string `json:"connection"`
+	Address    string `json:"address"`
+	Database   int    `json:"database"`
+	Error      string `json:"error,omitempty"`
+}
+
 // showMessage
 
 type ShowMessageParams struct {
@@ -132,8 +442,51 @@ type ShowMessageParams struct {
 	Message string      `json:"message"`
 }
 
+// client/registerCapability
+
+type RegistrationParams struct {
+	Registrations []Registration `json:"registrations"`
+}
+
+type Registration struct {
+	Id              string      `json:"id"`
+	Method          string      `json:"method"`
+	RegisterOptions interface{} `json:"registerOptions,omitempty"`
+}
+
+type DidChangeWatchedFilesRegistrationOptions struct {
+	Watchers []FileSystemWatcher `json:"watchers"`
+}
+
+type FileSystemWatcher struct {
+	GlobPattern string `json:"globPattern"`
+}
+
+// workspace/didChangeWatchedFiles
+
+type DidChangeWatchedFilesParams struct {
+	Changes []FileEvent `json:"changes"`
+}
+
+type FileChangeType int
+
+const (
+	Created FileChangeType = 1
+	Changed FileChangeType = 2
+	Deleted FileChangeType = 3
+)
+
+type FileEvent struct {
+	Uri  string         `json:"uri"`
+	Type FileChangeType `json:"type"`
+}
+
 // workspace/DidChangeConfiguration
 
 type DidChangeConfigurationParams struct {
-	Settings  interface{}   `json:"settings"`
+	Settings ConfigurationSettings `json:"settings"`
+}
+
+type ConfigurationSettings struct {
+	Redis json.RawMessage `json:"redis"`
 }
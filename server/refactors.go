@@ -0,0 +1,301 @@
+package server
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fagnercarvalho/redis-lsp/ast"
+	"github.com/fagnercarvalho/redis-lsp/completer"
+	"github.com/fagnercarvalho/redis-lsp/token"
+)
+
+// scanCount is the COUNT of the SCAN that replaces a KEYS.
+const scanCount = 100
+
+// notInScripts are the commands that cannot be called from a Lua script.
+var notInScripts = map[string]bool{
+	"DISCARD": true, "EVAL": true, "EVALSHA": true, "EVALSHA_RO": true, "EVAL_RO": true, "EXEC": true, "FCALL": true,
+	"FCALL_RO": true, "MONITOR": true, "MULTI": true, "PSUBSCRIBE": true, "PUNSUBSCRIBE": true, "SSUBSCRIBE": true,
+	"SUBSCRIBE": true, "SUNSUBSCRIBE": true, "UNSUBSCRIBE": true, "UNWATCH": true, "WATCH": true,
+}
+
+// refactor is a change of the selected statements that keeps what they do.
+type refactor struct {
+	Title string
+	Kind  CodeActionKind
+	Edits []TextEdit
+}
+
+// refactors returns the refactors available for the statements in the selection,
+// an empty selection selects the statement in its line.
+func refactors(text string, statements []ast.TokenList, selection Range) []refactor {
+	selected, ok := selectedStatements(statements, selection)
+	if !ok {
+		return nil
+	}
+
+	newline := "\n"
+	if strings.Contains(text, "\r\n") {
+		newline = "\r\n"
+	}
+
+	var found []refactor
+	for _, r := range []func([]ast.TokenList, string) (refactor, bool){
+		combineSets, splitMset, combineHsets, keysToScan, wrapInTransaction, extractScript,
+	} {
+		if refactor, ok := r(selected, newline); ok {
+			found = append(found, refactor)
+		}
+	}
+
+	return found
+}
+
+// selectedStatements returns the statements with a command in the lines of the selection,
+// ok is false when there are none or a comment is between them since it would be lost when they are replaced.
+func selectedStatements(statements []ast.TokenList, selection Range) ([]ast.TokenList, bool) {
+	// a selection of whole lines ends in the beginning of the next line
+	lastLine := selection.End.Line
+	if selection.End.Character == 0 && lastLine > selection.Start.Line {
+		lastLine--
+	}
+
+	var selected []ast.TokenList
+	first, last := -1, -1
+	for i, s := range statements {
+		command := s.GetCommand()
+		if command == nil || command.Line() < selection.Start.Line || command.Line() > lastLine {
+			continue
+		}
+
+		if first == -1 {
+			first = i
+		}
+
+		last = i
+		selected = append(selected, s)
+	}
+
+	// the comment of the last statement is after its last argument so it is kept
+	for i := first; i >= 0 && i < last; i++ {
+		if ast.GetComment(statements[i]) != nil {
+			return nil, false
+		}
+	}
+
+	return selected, len(selected) > 0
+}
+
+// combineSets replaces consecutive SET statements without options with a single MSET.
+func combineSets(selected []ast.TokenList, newline string) (refactor, bool) {
+	if len(selected) < 2 {
+		return refactor{}, false
+	}
+
+	words := []string{sameCase(selected[0].GetCommand().String(), "MSET")}
+	for _, s := range selected {
+		arguments := s.GetArguments()
+		if ast.CommandName(s) != "SET" || len(arguments) != 2 {
+			return refactor{}, false
+		}
+
+		words = append(words, arguments[0].String(), arguments[1].String())
+	}
+
+	edit := TextEdit{Range: statementsRange(selected), NewText: strings.Join(words, " ")}
+
+	return refactor{Title: "Combine SET statements into MSET", Kind: RefactorRewrite, Edits: []TextEdit{edit}}, true
+}
+
+// splitMset replaces a MSET with a SET statement for each key.
+func splitMset(selected []ast.TokenList, newline string) (refactor, bool) {
+	if len(selected) != 1 || ast.CommandName(selected[0]) != "MSET" {
+		return refactor{}, false
+	}
+
+	arguments := selected[0].GetArguments()
+	if len(arguments) < 4 || len(arguments)%2 != 0 {
+		return refactor{}, false
+	}
+
+	set := sameCase(selected[0].GetCommand().String(), "SET")
+	var lines []string
+	for i := 0; i < len(arguments); i += 2 {
+		lines = append(lines, strings.Join([]string{set, arguments[i].String(), arguments[i+1].String()}, " "))
+	}
+
+	edit := TextEdit{Range: statementsRange(selected), NewText: strings.Join(lines, newline)}
+
+	return refactor{Title: "Split MSET into SET statements", Kind: RefactorRewrite, Edits: []TextEdit{edit}}, true
+}
+
+// combineHsets replaces consecutive HSET statements on the same key with a single HSET.
+func combineHsets(selected []ast.TokenList, newline string) (refactor, bool) {
+	if len(selected) < 2 {
+		return refactor{}, false
+	}
+
+	key := ""
+	words := []string{selected[0].GetCommand().String()}
+	for i, s := range selected {
+		arguments := s.GetArguments()
+		if ast.CommandName(s) != "HSET" || len(arguments) < 3 || len(arguments)%2 != 1 {
+			return refactor{}, false
+		}
+
+		if i == 0 {
+			key = arguments[0].String()
+			words = append(words, key)
+		} else if arguments[0].String() != key {
+			return refactor{}, false
+		}
+
+		for _, a := range arguments[1:] {
+			words = append(words, a.String())
+		}
+	}
+
+	edit := TextEdit{Range: statementsRange(selected), NewText: strings.Join(words, " ")}
+
+	return refactor{Title: fmt.Sprintf("Combine HSET statements on %v", key), Kind: RefactorRewrite, Edits: []TextEdit{edit}}, true
+}
+
+// keysToScan replaces a KEYS with the first SCAN of the loop that returns the same keys without blocking the server,
+// since a document cannot loop the comment says how to continue it.
+func keysToScan(selected []ast.TokenList, newline string) (refactor, bool) {
+	if len(selected) != 1 || ast.CommandName(selected[0]) != "KEYS" || len(selected[0].GetArguments()) != 1 {
+		return refactor{}, false
+	}
+
+	typed := selected[0].GetCommand().String()
+	scan := fmt.Sprintf("%v 0 %v %v %v %v # repeat with the returned cursor until it is 0",
+		sameCase(typed, "SCAN"), sameCase(typed, "MATCH"), selected[0].GetArguments()[0].String(), sameCase(typed, "COUNT"), scanCount)
+
+	edit := TextEdit{Range: statementsRange(selected), NewText: scan}
+
+	return refactor{Title: "Convert KEYS into a SCAN loop", Kind: RefactorRewrite, Edits: []TextEdit{edit}}, true
+}
+
+// wrapInTransaction adds a MULTI before the selected statements and an EXEC after them.
+func wrapInTransaction(selected []ast.TokenList, newline string) (refactor, bool) {
+	for _, s := range selected {
+		switch ast.CommandName(s) {
+		case "MULTI", "EXEC", "DISCARD", "WATCH", "UNWATCH":
+			return refactor{}, false
+		}
+	}
+
+	typed := selected[0].GetCommand().String()
+	start := Position{Line: selected[0].GetCommand().Line()}
+
+	// EXEC goes after the comment of the last statement
+	var end Position
+	for _, t := range selected[len(selected)-1].GetTokens() {
+		if t.Type() != token.Space && t.Type() != token.Newline && t.Type() != token.Semicolon {
+			end = nodeRange(t).End
+		}
+	}
+
+	edits := []TextEdit{
+		{Range: Range{Start: start, End: start}, NewText: sameCase(typed, "MULTI") + newline},
+		{Range: Range{Start: end, End: end}, NewText: newline + sameCase(typed, "EXEC")},
+	}
+
+	return refactor{Title: "Wrap in MULTI/EXEC", Kind: RefactorRewrite, Edits: edits}, true
+}
+
+// extractScript replaces the statements with an EVAL running them in a Lua script, which makes them atomic.
+// Keys are passed as KEYS and arguments with variables as ARGV so they are not replaced inside the script,
+// the other arguments are written in the script.
+func extractScript(selected []ast.TokenList, newline string) (refactor, bool) {
+	var keys, arguments, calls []string
+	keyIndexes := map[string]int{}
+	for _, s := range selected {
+		command := ast.CommandName(s)
+		if _, ok := completer.CommandSince(command); !ok || notInScripts[command] {
+			return refactor{}, false
+		}
+
+		var values []string
+		for _, a := range s.GetArguments() {
+			values = append(values, a.String())
+		}
+
+		keyArguments := map[int]bool{}
+		for _, i := range completer.GetKeyIndexes(command, values) {
+			keyArguments[i] = true
+		}
+
+		call := []string{}
+		for _, word := range strings.Fields(command) {
+			call = append(call, luaString(word))
+		}
+
+		for i, value := range values {
+			switch {
+			case keyArguments[i]:
+				if _, ok := keyIndexes[value]; !ok {
+					keys = append(keys, value)
+					keyIndexes[value] = len(keys)
+				}
+
+				call = append(call, fmt.Sprintf("KEYS[%v]", keyIndexes[value]))
+			case strings.Contains(value, "${"):
+				arguments = append(arguments, value)
+				call = append(call, fmt.Sprintf("ARGV[%v]", len(arguments)))
+			default:
+				unquoted, err := token.Unquote(value)
+				if err != nil {
+					return refactor{}, false
+				}
+
+				call = append(call, luaString(unquoted))
+			}
+		}
+
+		calls = append(calls, fmt.Sprintf("redis.call(%v)", strings.Join(call, ", ")))
+	}
+
+	calls[len(calls)-1] = "return " + calls[len(calls)-1]
+
+	words := []string{sameCase(selected[0].GetCommand().String(), "EVAL"), token.Quote(strings.Join(calls, "\n")), fmt.Sprint(len(keys))}
+	words = append(words, keys...)
+	words = append(words, arguments...)
+
+	edit := TextEdit{Range: statementsRange(selected), NewText: strings.Join(words, " ")}
+
+	return refactor{Title: "Extract into an EVAL script", Kind: RefactorExtract, Edits: []TextEdit{edit}}, true
+}
+
+// statementsRange returns the range from the command of the first statement to the last argument of the last one.
+func statementsRange(statements []ast.TokenList) Range {
+	last := statements[len(statements)-1]
+	nodes := append([]ast.Node{last.GetCommand()}, last.GetArguments()...)
+
+	return Range{Start: nodeRange(statements[0].GetCommand()).Start, End: nodeRange(nodes[len(nodes)-1]).End}
+}
+
+// luaString returns the value as a single quoted Lua string.
+func luaString(value string) string {
+	var result strings.Builder
+	result.WriteByte('\'')
+	for i := 0; i < len(value); i++ {
+		switch c := value[i]; {
+		case c == '\'' || c == '\\':
+			result.WriteByte('\\')
+			result.WriteByte(c)
+		case c == '\n':
+			result.WriteString(`\n`)
+		case c == '\r':
+			result.WriteString(`\r`)
+		case c < 0x20 || c == 0x7f:
+			result.WriteString(fmt.Sprintf("\\%03d", c))
+		default:
+			result.WriteByte(c)
+		}
+	}
+
+	result.WriteByte('\'')
+
+	return result.String()
+}
@@ -0,0 +1,155 @@
+package server
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/fagnercarvalho/redis-lsp/ast"
+	"github.com/fagnercarvalho/redis-lsp/completer"
+	"github.com/fagnercarvalho/redis-lsp/token"
+)
+
+// keyOccurrence is a key argument of a statement.
+type keyOccurrence struct {
+	Key     string
+	Node    ast.Node
+	Command string
+	Access  completer.Access
+}
+
+// selectedKey returns the key in the selected token or false if the token is not a key.
+func selectedKey(statement ast.TokenList, selected ast.Node) (string, bool) {
+	var arguments []string
+	index := -1
+	for i, a := range statement.GetArguments() {
+		if a.Start() == selected.Start() {
+			index = i
+		}
+
+		arguments = append(arguments, a.String())
+	}
+
+	if index == -1 || !completer.IsKey(ast.CommandName(statement), arguments, index) {
+		return "", false
+	}
+
+	key, err := token.Unquote(selected.String())
+	if err != nil {
+		return "", false
+	}
+
+	return key, true
+}
+
+// keyOccurrences returns every key argument of the statements.
+func keyOccurrences(statements []ast.TokenList) []keyOccurrence {
+	var occurrences []keyOccurrence
+	for _, s := range statements {
+		command := ast.CommandName(s)
+		nodes := s.GetArguments()
+
+		var arguments []string
+		for _, a := range nodes {
+			arguments = append(arguments, a.String())
+		}
+
+		for _, i := range completer.GetKeyIndexes(command, arguments) {
+			key, err := token.Unquote(arguments[i])
+			if err != nil {
+				continue
+			}
+
+			occurrences = append(occurrences, keyOccurrence{
+				Key:     key,
+				Node:    nodes[i],
+				Command: command,
+				Access:  completer.KeyAccess(command, arguments, i),
+			})
+		}
+	}
+
+	return occurrences
+}
+
+// handleReferences returns every statement using the key under the position in the Redis files of the workspace.
+func (s Server) handleReferences(params *json.RawMessage) (interface{}, error) {
+	var request ReferenceParams
+	err := json.Unmarshal(*params, &request)
+	if err != nil {
+		return nil, err
+	}
+
+	if isScript(request.TextDocument.Uri) {
+		return nil, nil
+	}
+
+	statement, selected := ast.GetSelectedToken(ast.Parse(s.files[request.TextDocument.Uri]), request.Position.Line, request.Position.Character)
+	if selected == nil {
+		return nil, nil
+	}
+
+	key, ok := selectedKey(statement, selected)
+	if !ok {
+		return nil, nil
+	}
+
+	documents := s.workspaceDocuments(redisExtension)
+	documents[request.TextDocument.Uri] = s.files[request.TextDocument.Uri]
+
+	var uris []string
+	for uri := range documents {
+		uris = append(uris, uri)
+	}
+
+	sort.Strings(uris)
+
+	locations := []Location{}
+	for _, uri := range uris {
+		for _, o := range keyOccurrences(ast.Parse(documents[uri])) {
+			if o.Key == key {
+				locations = append(locations, Location{Uri: uri, Range: nodeRange(o.Node)})
+			}
+		}
+	}
+
+	return locations, nil
+}
+
+// handleDocumentHighlight highlights the key under the position in the document, distinguishing reads from writes.
+func (s Server) handleDocumentHighlight(params *json.RawMessage) (interface{}, error) {
+	var request ReferenceParams
+	err := json.Unmarshal(*params, &request)
+	if err != nil {
+		return nil, err
+	}
+
+	if isScript(request.TextDocument.Uri) {
+		return nil, nil
+	}
+
+	statements := ast.Parse(s.files[request.TextDocument.Uri])
+	statement, selected := ast.GetSelectedToken(statements, request.Position.Line, request.Position.Character)
+	if selected == nil {
+		return nil, nil
+	}
+
+	key, ok := selectedKey(statement, selected)
+	if !ok {
+		return nil, nil
+	}
+
+	kinds := map[completer.Access]DocumentHighlightKind{
+		completer.UnknownAccess: TextHighlight,
+		completer.ReadAccess:    ReadHighlight,
+		completer.WriteAccess:   WriteHighlight,
+	}
+
+	highlights := []DocumentHighlight{}
+	for _, o := range keyOccurrences(statements) {
+		if o.Key == key {
+			highlights = append(highlights, DocumentHighlight{Range: nodeRange(o.Node), Kind: kinds[o.Access]})
+		}
+	}
+
+	return highlights, nil
+}
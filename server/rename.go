@@ -0,0 +1,214 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/fagnercarvalho/redis-lsp/ast"
+	"github.com/fagnercarvalho/redis-lsp/token"
+	"github.com/sourcegraph/jsonrpc2"
+)
+
+const renameKeyCommand = "redis.renameKey"
+
+// renamePrefix returns the part of the key that is renamed for the position: the key until the end
+// of the segment under the position (e.g. "user:42" for the position in 42 in "user:42:session").
+func (s Server) renamePrefix(key string, selected ast.Node, position Position) string {
+	delimiter := s.settings.KeyDelimiter
+
+	// the offset in the value only matches the offset in the token for unquoted keys
+	if delimiter == "" || strings.HasPrefix(selected.String(), "\"") || strings.HasPrefix(selected.String(), "'") {
+		return key
+	}
+
+	offset := position.Character - selected.LineStart()
+	if offset < 0 || offset >= len(key) {
+		return key
+	}
+
+	end := strings.Index(key[offset:], delimiter)
+	if end == -1 {
+		return key
+	}
+
+	return key[:offset+end]
+}
+
+// matchesPrefix reports whether the key is the prefix itself or starts with the prefix followed by the delimiter.
+func matchesPrefix(key string, prefix string, delimiter string) bool {
+	return key == prefix || (delimiter != "" && strings.HasPrefix(key, prefix+delimiter))
+}
+
+func (s Server) handlePrepareRename(params *json.RawMessage) (interface{}, error) {
+	var request RenameParams
+	err := json.Unmarshal(*params, &request)
+	if err != nil {
+		return nil, err
+	}
+
+	if isScript(request.TextDocument.Uri) {
+		return nil, nil
+	}
+
+	statement, selected := ast.GetSelectedToken(ast.Parse(s.files[request.TextDocument.Uri]), request.Position.Line, request.Position.Character)
+	if selected == nil {
+		return nil, nil
+	}
+
+	key, ok := selectedKey(statement, selected)
+	if !ok {
+		return nil, nil
+	}
+
+	prefix := s.renamePrefix(key, selected, request.Position)
+	r := nodeRange(selected)
+	if prefix != key {
+		r.End.Character = r.Start.Character + len(prefix)
+	}
+
+	return PrepareRenameResult{Range: r, Placeholder: prefix}, nil
+}
+
+// handleRename renames the key or key prefix under the position in every key argument of the workspace Redis files,
+// values that happen to be equal to the key are not changed.
+func (s Server) handleRename(params *json.RawMessage) (interface{}, error) {
+	var request RenameParams
+	err := json.Unmarshal(*params, &request)
+	if err != nil {
+		return nil, err
+	}
+
+	if isScript(request.TextDocument.Uri) {
+		return nil, nil
+	}
+
+	if request.NewName == "" {
+		return nil, errors.New("the key name can not be empty")
+	}
+
+	statement, selected := ast.GetSelectedToken(ast.Parse(s.files[request.TextDocument.Uri]), request.Position.Line, request.Position.Character)
+	if selected == nil {
+		return nil, errors.New("only keys can be renamed")
+	}
+
+	key, ok := selectedKey(statement, selected)
+	if !ok {
+		return nil, errors.New("only keys can be renamed")
+	}
+
+	prefix := s.renamePrefix(key, selected, request.Position)
+
+	documents := s.workspaceDocuments(redisExtension)
+	documents[request.TextDocument.Uri] = s.files[request.TextDocument.Uri]
+
+	edit := WorkspaceEdit{Changes: map[string][]TextEdit{}}
+	for uri, text := range documents {
+		for _, o := range keyOccurrences(ast.Parse(text)) {
+			if !matchesPrefix(o.Key, prefix, s.settings.KeyDelimiter) {
+				continue
+			}
+
+			newKey := request.NewName + o.Key[len(prefix):]
+			edit.Changes[uri] = append(edit.Changes[uri], TextEdit{Range: nodeRange(o.Node), NewText: keyText(o.Node.String(), newKey)})
+		}
+	}
+
+	return edit, nil
+}
+
+// keyText returns how the key is written keeping the quotes of the original token when it had them.
+func keyText(original string, key string) string {
+	if strings.HasPrefix(original, "'") && !strings.ContainsAny(key, "'\\") {
+		return "'" + key + "'"
+	}
+
+	if strings.HasPrefix(original, "\"") || token.NeedsQuotes(key) {
+		return token.Quote(key)
+	}
+
+	return key
+}
+
+// renameKey renames a key or, when the third argument is true, every key with the prefix on the server.
+// The arguments are the key or prefix, the new name, whether it is a prefix and the document to take the connection from.
+func (s Server) renameKey(ctx context.Context, arguments []interface{}, conn *jsonrpc2.Conn) (interface{}, error) {
+	if s.settings.Safety.SafeMode {
+		return showError(ctx, conn, "Keys are not renamed on the server in safe mode")
+	}
+
+	if len(arguments) < 2 {
+		return showError(ctx, conn, "The key and the new name are required to rename a key")
+	}
+
+	key, _ := arguments[0].(string)
+	newName, _ := arguments[1].(string)
+	if key == "" || newName == "" {
+		return showError(ctx, conn, "The key and the new name are required to rename a key")
+	}
+
+	prefix := false
+	if len(arguments) > 2 {
+		prefix, _ = arguments[2].(bool)
+	}
+
+	var uri string
+	if len(arguments) > 3 {
+		uri, _ = arguments[3].(string)
+	}
+
+	redis, err := s.connection(uri)
+	if err != nil {
+		return showError(ctx, conn, err.Error())
+	}
+
+	keys := []string{key}
+	if prefix {
+		// the prefix itself is renamed in the documents too (see matchesPrefix) so it is renamed when it is a key
+		exists, err := redis.Exists(ctx, key)
+		if err != nil {
+			return showError(ctx, conn, err.Error())
+		}
+
+		if !exists {
+			keys = nil
+		}
+
+		matches, err := redis.ScanKeys(ctx, escapePattern(key+s.settings.KeyDelimiter)+"*")
+		if err != nil {
+			return showError(ctx, conn, err.Error())
+		}
+
+		keys = append(keys, matches...)
+	}
+
+	renamed := 0
+	for _, k := range keys {
+		err = redis.Rename(ctx, k, newName+k[len(key):])
+		if err != nil {
+			return showError(ctx, conn, fmt.Sprintf("Error while renaming %v (%v keys renamed): %v", k, renamed, err))
+		}
+
+		renamed++
+	}
+
+	message := ShowMessageParams{Message: fmt.Sprintf("%v keys renamed", renamed), Type: Info}
+
+	return renamed, conn.Notify(ctx, "window/showMessage", message)
+}
+
+// escapePattern escapes the characters that have a special meaning in the glob-style patterns of SCAN.
+func escapePattern(value string) string {
+	var result strings.Builder
+	for _, r := range value {
+		if strings.ContainsRune(`*?[]\`, r) {
+			result.WriteRune('\\')
+		}
+
+		result.WriteRune(r)
+	}
+
+	return result.String()
+}
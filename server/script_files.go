@@ -0,0 +1,308 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/fagnercarvalho/redis-lsp/ast"
+	"github.com/fagnercarvalho/redis-lsp/completer"
+	"github.com/fagnercarvalho/redis-lsp/lua"
+	"github.com/fagnercarvalho/redis-lsp/token"
+	"github.com/sourcegraph/jsonrpc2"
+)
+
+// luaExtension is the extension of the Lua script files that can be run with EVAL.
+const luaExtension = ".lua"
+
+const (
+	runScriptCommand  = "redis.runScript"
+	loadScriptCommand = "redis.loadScript"
+)
+
+// isScript reports whether the document is a Lua script file instead of a file with Redis commands.
+func isScript(uri string) bool {
+	return strings.EqualFold(filepath.Ext(uriToPath(uri)), luaExtension)
+}
+
+// handleCodeLens shows the actions to run a script file with EVAL and to load it with SCRIPT LOAD,
+// function libraries are (re)loaded with FUNCTION LOAD REPLACE instead.
+func (s Server) handleCodeLens(params *json.RawMessage) (interface{}, error) {
+	var request CodeLensParams
+	err := json.Unmarshal(*params, &request)
+	if err != nil {
+		return nil, err
+	}
+
+	uri := request.TextDocument.Uri
+	if !isScript(uri) {
+		return []CodeLens{}, nil
+	}
+
+	text := s.files[uri]
+	if library, ok := lua.ParseLibrary(text); ok {
+		title := "Load library with FUNCTION LOAD REPLACE"
+		if library.Name != "" {
+			title = fmt.Sprintf("Load library %v with FUNCTION LOAD REPLACE", library.Name)
+		}
+
+		return []CodeLens{{Command: &Command{Title: title, Command: loadFunctionCommand, Arguments: []interface{}{uri}}}}, nil
+	}
+
+	header := lua.ParseHeader(text)
+
+	run := Command{Title: "Run with EVAL", Command: runScriptCommand, Arguments: []interface{}{uri}}
+	if header.Declared {
+		run.Title = fmt.Sprintf("Run with EVAL (KEYS: %v, ARGV: %v)", strings.Join(header.Keys, " "), strings.Join(header.Arguments, " "))
+		run.Arguments = append(run.Arguments, header.Keys, header.Arguments)
+	}
+
+	load := Command{Title: "Load with SCRIPT LOAD", Command: loadScriptCommand, Arguments: []interface{}{uri}}
+	if sha := scriptSHA(text); s.scripts[sha] == uri {
+		load.Title = fmt.Sprintf("Loaded as %v", sha)
+	}
+
+	return []CodeLens{{Command: &run}, {Command: &load}}, nil
+}
+
+// runScript runs a script file with EVAL, the arguments are the document URI and optionally the keys and the arguments
+// passed to the script (clients can prompt for them), otherwise the ones declared in the header of the script are used.
+func (s Server) runScript(ctx context.Context, arguments []interface{}, conn *jsonrpc2.Conn) (interface{}, error) {
+	uri, text, err := s.scriptFile(arguments)
+	if err != nil {
+		return showError(ctx, conn, err.Error())
+	}
+
+	header := lua.ParseHeader(text)
+	keys, scriptArguments := header.Keys, header.Arguments
+	if len(arguments) > 1 {
+		keys = interfaceValues(arguments[1])
+	}
+
+	if len(arguments) > 2 {
+		scriptArguments = interfaceValues(arguments[2])
+	}
+
+	command := []interface{}{"EVAL", text, strconv.Itoa(len(keys))}
+	for _, k := range keys {
+		command = append(command, k)
+	}
+
+	for _, a := range scriptArguments {
+		command = append(command, a)
+	}
+
+	val, ok, err := s.executeFor(ctx, conn, uri, command)
+	if !ok {
+		return nil, err
+	}
+
+	logMessage := LogMessageParams{Message: fmt.Sprintf("%v", val), Type: Log}
+
+	return val, conn.Notify(ctx, "window/logMessage", logMessage)
+}
+
+// loadScript loads a script file with SCRIPT LOAD and records the SHA1 returned by Redis
+// so EVALSHA statements with it can go to the file.
+func (s Server) loadScript(ctx context.Context, arguments []interface{}, conn *jsonrpc2.Conn) (interface{}, error) {
+	uri, text, err := s.scriptFile(arguments)
+	if err != nil {
+		return showError(ctx, conn, err.Error())
+	}
+
+	command := []interface{}{"SCRIPT", "LOAD", text}
+	val, ok, err := s.executeFor(ctx, conn, uri, command)
+	if !ok {
+		return nil, err
+	}
+
+	sha := fmt.Sprintf("%v", val)
+	s.scripts[sha] = uri
+
+	message := ShowMessageParams{Message: fmt.Sprintf("%v loaded as %v", filepath.Base(uriToPath(uri)), sha), Type: Info}
+
+	return sha, conn.Notify(ctx, "window/showMessage", message)
+}
+
+// scriptFile returns the URI in the first argument of a command and the text of the script file,
+// files that are not open are read from the disk.
+func (s Server) scriptFile(arguments []interface{}) (string, string, error) {
+	var uri string
+	if len(arguments) > 0 {
+		uri, _ = arguments[0].(string)
+	}
+
+	if !isScript(uri) {
+		return "", "", fmt.Errorf("the URI of a %v file is required", luaExtension)
+	}
+
+	if text, ok := s.files[uri]; ok {
+		return uri, text, nil
+	}
+
+	data, err := ioutil.ReadFile(uriToPath(uri))
+	if err != nil {
+		return "", "", err
+	}
+
+	return uri, string(data), nil
+}
+
+// handleDefinition goes from the SHA1 of an EVALSHA statement to the script with it
+// and from the function of an FCALL statement to the library file that registers it.
+func (s Server) handleDefinition(params *json.RawMessage) (interface{}, error) {
+	var request TextDocumentPositionParams
+	err := json.Unmarshal(*params, &request)
+	if err != nil {
+		return nil, err
+	}
+
+	if isScript(request.TextDocument.Uri) {
+		return nil, nil
+	}
+
+	statements := ast.Parse(s.files[request.TextDocument.Uri])
+	statement, selected := ast.GetSelectedToken(statements, request.Position.Line, request.Position.Character)
+	if selected == nil {
+		return nil, nil
+	}
+
+	command := ast.CommandName(statement)
+	arguments := statement.GetArguments()
+	if len(arguments) == 0 || arguments[0].Start() != selected.Start() {
+		return nil, nil
+	}
+
+	value, err := token.Unquote(selected.String())
+	if err != nil {
+		return nil, nil
+	}
+
+	switch command {
+	case "EVALSHA", "EVALSHA_RO":
+		return s.scriptDefinitions(strings.ToLower(value)), nil
+	case "FCALL", "FCALL_RO":
+		return s.functionDefinitions(value), nil
+	}
+
+	return nil, nil
+}
+
+// scriptDefinitions returns the script files with the SHA1 or that were loaded with it
+// and the EVAL and SCRIPT LOAD statements of the workspace with the same script.
+func (s Server) scriptDefinitions(sha string) []Location {
+	locations := []Location{}
+	found := map[string]bool{}
+
+	scripts := s.workspaceDocuments(luaExtension)
+	for _, uri := range sortedURIs(scripts) {
+		if scriptSHA(scripts[uri]) == sha {
+			locations = append(locations, Location{Uri: uri})
+			found[uri] = true
+		}
+	}
+
+	// the file may have been changed after it was loaded
+	if uri, ok := s.scripts[sha]; ok && !found[uri] {
+		locations = append(locations, Location{Uri: uri})
+	}
+
+	documents := s.workspaceDocuments(redisExtension)
+	for _, uri := range sortedURIs(documents) {
+		for _, o := range scriptOccurrences(ast.Parse(documents[uri])) {
+			if o.SHA == sha && o.Command != "EVALSHA" && o.Command != "EVALSHA_RO" {
+				locations = append(locations, Location{Uri: uri, Range: nodeRange(o.Node)})
+			}
+		}
+	}
+
+	return locations
+}
+
+// scriptFileHover shows the values declared in the header for KEYS[n] and ARGV[n] and the documentation of the redis functions.
+func scriptFileHover(text string, line int, position int) *Hover {
+	offset := textOffset(text, line, position)
+
+	header := lua.ParseHeader(text)
+	for _, index := range lua.Indexes(text) {
+		if offset >= index.Start && offset < index.End {
+			return indexHover(index, header.Keys, header.Arguments, Range{Start: textPosition(text, index.Start), End: textPosition(text, index.End)})
+		}
+	}
+
+	tokens, _ := lua.Tokenize(text)
+	for i, t := range tokens {
+		if t.Type != lua.Name || offset < t.Start || offset > t.End || i < 2 || tokens[i-1].Value != "." || tokens[i-2].Value != "redis" {
+			continue
+		}
+
+		name := "redis." + t.Value
+		documentation, err := completer.GetDocumentation(name)
+		if err != nil {
+			return nil
+		}
+
+		return &Hover{
+			Contents: MarkupContent{Kind: Markdown, Value: fmt.Sprintf("### %v \n %v", name, string(documentation))},
+			Range:    &Range{Start: textPosition(text, tokens[i-2].Start), End: textPosition(text, t.End)},
+		}
+	}
+
+	return nil
+}
+
+// textOffset returns the offset in the text of a line and a character in the line.
+func textOffset(text string, line int, position int) int {
+	offset := 0
+	for i := 0; i < line; i++ {
+		newline := strings.IndexByte(text[offset:], '\n')
+		if newline == -1 {
+			return len(text)
+		}
+
+		offset += newline + 1
+	}
+
+	if offset+position > len(text) {
+		return len(text)
+	}
+
+	return offset + position
+}
+
+// textPosition returns the line and the character in the line of an offset in the text.
+func textPosition(text string, offset int) Position {
+	before := text[:offset]
+	return Position{
+		Line:      strings.Count(before, "\n"),
+		Character: offset - strings.LastIndexByte(before, '\n') - 1,
+	}
+}
+
+func sortedURIs(documents map[string]string) []string {
+	var uris []string
+	for uri := range documents {
+		uris = append(uris, uri)
+	}
+
+	sort.Strings(uris)
+
+	return uris
+}
+
+// interfaceValues returns the values of a JSON array as strings (e.g. numbers passed as arguments of a script).
+func interfaceValues(value interface{}) []string {
+	values, _ := value.([]interface{})
+
+	var result []string
+	for _, v := range values {
+		result = append(result, fmt.Sprintf("%v", v))
+	}
+
+	return result
+}
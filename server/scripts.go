@@ -0,0 +1,100 @@
+package server
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/fagnercarvalho/redis-lsp/ast"
+	"github.com/fagnercarvalho/redis-lsp/lua"
+	"github.com/fagnercarvalho/redis-lsp/token"
+)
+
+// scriptSHA returns the SHA1 digest Redis uses to identify a Lua script (e.g. in EVALSHA).
+func scriptSHA(script string) string {
+	sum := sha1.Sum([]byte(script))
+	return hex.EncodeToString(sum[:])
+}
+
+// scriptArgument returns KEYS[n] or ARGV[n] when the node is a key or an argument passed to the script of an EVAL or EVALSHA statement.
+func scriptArgument(statement ast.TokenList, node ast.Node) (string, bool) {
+	keys, arguments, ok := ast.GetScriptArguments(statement)
+	if !ok {
+		return "", false
+	}
+
+	for i, k := range keys {
+		if k.Start() == node.Start() {
+			return fmt.Sprintf("KEYS[%v]", i+1), true
+		}
+	}
+
+	for i, a := range arguments {
+		if a.Start() == node.Start() {
+			return fmt.Sprintf("ARGV[%v]", i+1), true
+		}
+	}
+
+	return "", false
+}
+
+// scriptIndexHover returns the value passed to the script for a KEYS[n] or ARGV[n] in the script of an EVAL statement.
+func scriptIndexHover(statement ast.TokenList, selected ast.Node, line int, position int) *Hover {
+	script := ast.GetScript(statement)
+	if script == nil || script.Start() != selected.Start() || script.Type() != token.String {
+		return nil
+	}
+
+	source, offsets, err := token.UnquoteOffsets(script.String())
+	if err != nil {
+		return nil
+	}
+
+	keys, arguments, _ := ast.GetScriptArguments(statement)
+
+	offset := ast.PositionOffset(script, line, position)
+	for _, index := range lua.Indexes(source) {
+		start, end := offsets[index.Start], offsets[index.End]
+		if offset < start || offset >= end {
+			continue
+		}
+
+		var keyValues, argumentValues []string
+		for _, k := range keys {
+			keyValues = append(keyValues, k.String())
+		}
+
+		for _, a := range arguments {
+			argumentValues = append(argumentValues, a.String())
+		}
+
+		startLine, startCharacter := ast.OffsetPosition(script, start)
+		endLine, endCharacter := ast.OffsetPosition(script, end)
+
+		return indexHover(index, keyValues, argumentValues, Range{
+			Start: Position{Line: startLine, Character: startCharacter},
+			End:   Position{Line: endLine, Character: endCharacter},
+		})
+	}
+
+	return nil
+}
+
+// indexHover returns the value of a KEYS[n] or ARGV[n] from the keys and arguments passed to the script.
+func indexHover(index lua.Index, keys []string, arguments []string, indexRange Range) *Hover {
+	values := keys
+	if index.Name == "ARGV" {
+		values = arguments
+	}
+
+	name := fmt.Sprintf("%v[%v]", index.Name, index.Number)
+	value := fmt.Sprintf("### %v \n Not passed to the script.", name)
+	if index.Number >= 1 && index.Number <= len(values) {
+		value = fmt.Sprintf("### %v \n```\n%v\n```", name, values[index.Number-1])
+	}
+
+	return &Hover{
+		Contents: MarkupContent{Kind: Markdown, Value: value},
+		Range:    &indexRange,
+	}
+}
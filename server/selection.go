@@ -0,0 +1,132 @@
+package server
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/fagnercarvalho/redis-lsp/ast"
+	"github.com/fagnercarvalho/redis-lsp/completer"
+	"github.com/fagnercarvalho/redis-lsp/token"
+)
+
+// handleSelectionRange returns, for each position, the ranges to expand the selection to:
+// token, argument group (e.g. "EX 10"), statement, transaction, region and the whole document.
+func (s Server) handleSelectionRange(params *json.RawMessage) (interface{}, error) {
+	var request SelectionRangeParams
+	err := json.Unmarshal(*params, &request)
+	if err != nil {
+		return nil, err
+	}
+
+	if isScript(request.TextDocument.Uri) {
+		return nil, nil
+	}
+
+	text := s.files[request.TextDocument.Uri]
+	statements := ast.Parse(text)
+	lines := strings.Split(text, "\n")
+
+	var blocks []Range
+	for _, r := range append(ast.GetTransactions(statements), ast.GetRegions(statements)...) {
+		blocks = append(blocks, blockSymbol(lines, r, r.Name, NamespaceSymbol).Range)
+	}
+
+	lastLine := len(lines) - 1
+	document := Range{End: Position{Line: lastLine, Character: len(strings.TrimSuffix(lines[lastLine], "\r"))}}
+
+	result := []SelectionRange{}
+	for _, position := range request.Positions {
+		var containing []Range
+		for _, b := range blocks {
+			if contains(b, Range{Start: position, End: position}) {
+				containing = append(containing, b)
+			}
+		}
+
+		// the blocks with the position are nested so the inner ones are the smallest
+		sort.SliceStable(containing, func(i, j int) bool {
+			return smaller(containing[i], containing[j])
+		})
+
+		ranges := append(selectionRanges(statements, position), containing...)
+		ranges = append(ranges, document)
+
+		result = append(result, *selectionRange(ranges))
+	}
+
+	return result, nil
+}
+
+// smaller reports whether the range spans fewer lines than the other one, or fewer characters when they span the same lines.
+func smaller(a Range, b Range) bool {
+	if a.End.Line-a.Start.Line != b.End.Line-b.Start.Line {
+		return a.End.Line-a.Start.Line < b.End.Line-b.Start.Line
+	}
+
+	return a.End.Character-a.Start.Character < b.End.Character-b.Start.Character
+}
+
+// selectionRanges returns the ranges of the token, argument group and statement in the position.
+func selectionRanges(statements []ast.TokenList, position Position) []Range {
+	statement, selected := ast.GetSelectedToken(statements, position.Line, position.Character)
+	if statement == nil {
+		for _, st := range statements {
+			if contains(statementRange(st), Range{Start: position, End: position}) {
+				return []Range{statementRange(st)}
+			}
+		}
+
+		return nil
+	}
+
+	ranges := []Range{nodeRange(selected)}
+
+	arguments := statement.GetArguments()
+	index := -1
+	var values []string
+	for i, a := range arguments {
+		if a.Start() == selected.Start() {
+			index = i
+		}
+
+		value, _ := token.Unquote(a.String())
+		values = append(values, value)
+	}
+
+	syntax, ok := completer.CommandArguments(ast.CommandName(statement))
+	if ok && index != -1 {
+		start, end, ok := completer.ArgumentGroup(syntax, completer.Match(syntax, values), index)
+		if ok {
+			ranges = append(ranges, Range{
+				Start: nodeRange(arguments[start]).Start,
+				End:   nodeRange(arguments[end]).End,
+			})
+		}
+	}
+
+	return append(ranges, statementRange(statement))
+}
+
+// selectionRange links the ranges from the innermost to the outermost,
+// ranges that do not contain the previous one or are equal to it are skipped.
+func selectionRange(ranges []Range) *SelectionRange {
+	var linked []Range
+	for _, r := range ranges {
+		if len(linked) > 0 {
+			previous := linked[len(linked)-1]
+			if r == previous || !contains(r, previous) {
+				continue
+			}
+		}
+
+		linked = append(linked, r)
+	}
+
+	var result *SelectionRange
+	for i := len(linked) - 1; i >= 0; i-- {
+		result = &SelectionRange{Range: linked[i], Parent: result}
+	}
+
+	return result
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/fagnercarvalho/redis-lsp/client"
 	"github.com/fagnercarvalho/redis-lsp/completer"
 	"github.com/fagnercarvalho/redis-lsp/token"
+	"github.com/fagnercarvalho/redis-lsp/variables"
 	"github.com/go-redis/redis/v8"
 	"github.com/sourcegraph/jsonrpc2"
 	"log"
@@ -25,19 +26,55 @@ import (
 
 type Server struct {
 	files map[string]string
-	redis client.Redis
-	completer completer.Completer
+	versions map[string]int
+	keyInfo map[string]*keyInfoCache
+	capabilities *ClientCapabilities
+	settings *Settings
+	sources *settingsSources
+	roots *[]string
+	defaultProfile *client.Profile
+	connections *client.Manager
+	documentConnections map[string]string
+	completer *completer.Completer
+
+	// scripts are the URIs of the script files loaded with SCRIPT LOAD by SHA1
+	scripts map[string]string
+
+	// functions are the functions that can be called with FCALL by connection, see functionNames
+	functions map[string]*functionCache
 }
 
 func New(address string, username string, password string, db int, dbCache bool) (Server, error) {
-	client, err := client.New(address, username, password, db, dbCache)
+	profile := client.Profile{Name: defaultConnection, Address: address, Username: username, Password: password, Database: db}
+	connections := client.NewManager([]client.Profile{profile}, defaultConnection, dbCache)
+
+	_, err := connections.Client(defaultConnection)
 	if err != nil {
 		return Server{}, err
 	}
 
-	completer := completer.Completer{Users: client.Users, Keys: client.Keys}
+	settings := defaultSettings()
+	completer := &completer.Completer{Case: settings.Completion.Case}
+
+	server := Server{
+		files:               map[string]string{},
+		versions:            map[string]int{},
+		keyInfo:             map[string]*keyInfoCache{},
+		capabilities:        &ClientCapabilities{},
+		settings:            &settings,
+		sources:             &settingsSources{},
+		roots:               &[]string{},
+		defaultProfile:      &profile,
+		connections:         connections,
+		documentConnections: map[string]string{},
+		scripts:             map[string]string{},
+		functions:           map[string]*functionCache{},
+		completer:           completer,
+	}
+
+	completer.Environment = server.envFile
 
-	return Server{files: map[string]string{}, redis: client, completer: completer}, nil
+	return server, nil
 }
 
 func (s Server) Handle(ctx context.Context, conn *jsonrpc2.Conn, request *jsonrpc2.Request) (result interface{}, err error) {
@@ -45,32 +82,83 @@ func (s Server) Handle(ctx context.Context, conn *jsonrpc2.Conn, request *jsonrp
 
 	switch request.Method {
 	case "initialize":
-		return handleInitialize()
+		return s.handleInitialize(request.Params)
 	case "textDocument/completion":
 		return s.handleCompletion(request.Params)
 	case "textDocument/didOpen":
-		return s.handleOpen(request.Params)
+		return s.handleOpen(ctx, request.Params, conn)
 	case "textDocument/didChange":
-		return s.handleChange(request.Params)
+		return s.handleChange(ctx, request.Params, conn)
+	case "textDocument/hover":
+		return s.handleHover(ctx, request.Params)
 	case "completionItem/resolve":
 		return s.handleCompletionResolve(request.Params)
 	case "workspace/executeCommand":
 		return s.handleWorkspaceExecuteCommand(ctx, request.Params, conn)
 	case "initialized":
-		// do nothing
-		return nil, nil
+		return s.handleInitialized(ctx, conn)
 	case "workspace/didChangeConfiguration":
-		// TODO: implement method
-		return s.handleWorkspaceDidChangeConfiguration(request.Params)
+		return s.handleWorkspaceDidChangeConfiguration(ctx, request.Params, conn)
+	case "textDocument/documentSymbol":
+		return s.handleDocumentSymbol(request.Params)
+	case "textDocument/selectionRange":
+		return s.handleSelectionRange(request.Params)
+	case "textDocument/foldingRange":
+		return s.handleFoldingRange(request.Params)
+	case "textDocument/references":
+		return s.handleReferences(request.Params)
+	case "textDocument/documentHighlight":
+		return s.handleDocumentHighlight(request.Params)
+	case "textDocument/prepareRename":
+		return s.handlePrepareRename(request.Params)
+	case "textDocument/rename":
+		return s.handleRename(request.Params)
+	case "workspace/symbol":
+		return s.handleWorkspaceSymbol(request.Params)
+	case "textDocument/formatting":
+		return s.handleFormatting(request.Params)
+	case "textDocument/rangeFormatting":
+		return s.handleRangeFormatting(request.Params)
+	case "textDocument/definition":
+		return s.handleDefinition(request.Params)
+	case "textDocument/codeLens":
+		return s.handleCodeLens(request.Params)
+	case "textDocument/codeAction":
+		return s.handleCodeAction(request.Params)
+	case "workspace/didChangeWatchedFiles":
+		return s.handleDidChangeWatchedFiles(ctx, request.Params, conn)
 	}
-	// TODO: implement hover
 	errorMessage := fmt.Sprintf("method not handled: %v", request.Method)
 	log.Println(errorMessage)
 
 	return nil, &jsonrpc2.Error{Code: jsonrpc2.CodeMethodNotFound, Message: errorMessage}
 }
 
-func handleInitialize() (interface{}, error) {
+func (s Server) handleInitialize(params *json.RawMessage) (interface{}, error) {
+	var request InitializeParams
+	err := json.Unmarshal(*params, &request)
+	if err != nil {
+		return nil, err
+	}
+
+	*s.capabilities = request.Capabilities
+
+	var roots []string
+	for _, f := range request.WorkspaceFolders {
+		roots = append(roots, uriToPath(f.Uri))
+	}
+
+	if len(roots) == 0 && request.RootUri != "" {
+		roots = append(roots, uriToPath(request.RootUri))
+	}
+
+	*s.roots = roots
+
+	// the workspace configuration files are applied once the client is initialized
+	if len(request.InitializationOptions) > 0 && string(request.InitializationOptions) != "null" {
+		s.sources.client = request.InitializationOptions
+	}
+
 	return InitializeResult{
 		Capabilities: Capabilities{
 			TextDocumentSync: KindFull,
@@ -78,15 +166,39 @@ func handleInitialize() (interface{}, error) {
 				ResolveProvider:   true,
 			},
 			ExecuteCommandProvider: ExecuteCommandOptions{
-				Commands: []string { "server.executeCommand" },
+				Commands: []string { "server.executeCommand", switchConnectionCommand, renameKeyCommand, runScriptCommand, loadScriptCommand, loadFunctionCommand },
 			},
-			HoverProvider: false,
+			HoverProvider: true,
 			SelectionRangeProvider: true,
+			DocumentSymbolProvider: true,
+			FoldingRangeProvider: true,
+			ReferencesProvider: true,
+			DocumentHighlightProvider: true,
+			RenameProvider: RenameOptions{PrepareProvider: true},
+			WorkspaceSymbolProvider: true,
+			DocumentFormattingProvider: true,
+			DocumentRangeFormattingProvider: true,
+			DefinitionProvider: true,
+			CodeLensProvider: &CodeLensOptions{},
+			CodeActionProvider: &CodeActionOptions{CodeActionKinds: []CodeActionKind{QuickFix, RefactorExtract, RefactorRewrite}},
 		},
 	}, nil
 }
 
-func (s Server) handleOpen(params *json.RawMessage) (interface{}, error) {
+func (s Server) handleInitialized(ctx context.Context, conn *jsonrpc2.Conn) (interface{}, error) {
+	err := s.loadWorkspaceSettings(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.capabilities.Workspace.DidChangeWatchedFiles.DynamicRegistration {
+		go s.watchConfigFiles(conn)
+	}
+
+	return nil, s.notifyConnection(ctx, conn, "")
+}
+
+func (s Server) handleOpen(ctx context.Context, params *json.RawMessage, conn *jsonrpc2.Conn) (interface{}, error) {
 	var request DidOpenTextDocumentParams
 	err := json.Unmarshal(*params, &request)
 	if err != nil {
@@ -94,11 +206,21 @@ func (s Server) handleOpen(params *json.RawMessage) (interface{}, error) {
 	}
 
 	s.files[request.TextDocument.Uri] = request.TextDocument.Text
+	s.versions[request.TextDocument.Uri] = request.TextDocument.Version
 
-	return nil, nil
+	if isScript(request.TextDocument.Uri) {
+		s.clearFunctions()
+	}
+
+	err = s.notifyConnection(ctx, conn, request.TextDocument.Uri)
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, s.publishDiagnostics(ctx, conn, request.TextDocument.Uri)
 }
 
-func (s Server) handleChange(params *json.RawMessage) (interface{}, error) {
+func (s Server) handleChange(ctx context.Context, params *json.RawMessage, conn *jsonrpc2.Conn) (interface{}, error) {
 	var request DidChangeTextDocumentParams
 	err := json.Unmarshal(*params, &request)
 	if err != nil {
@@ -106,8 +228,21 @@ func (s Server) handleChange(params *json.RawMessage) (interface{}, error) {
 	}
 
 	s.files[request.TextDocument.Uri] = request.ContentChanges[0].Text
+	s.versions[request.TextDocument.Uri] = request.TextDocument.Version
 
-	return nil, nil
+	if isScript(request.TextDocument.Uri) {
+		s.clearFunctions()
+	}
+
+	// the connection directive may have been changed
+	if s.connectionProfile(request.TextDocument.Uri).Name != s.documentConnections[request.TextDocument.Uri] {
+		err = s.notifyConnection(ctx, conn, request.TextDocument.Uri)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return nil, s.publishDiagnostics(ctx, conn, request.TextDocument.Uri)
 }
 
 func (s Server) handleCompletion(params *json.RawMessage) (interface{}, error) {
@@ -118,16 +253,93 @@ func (s Server) handleCompletion(params *json.RawMessage) (interface{}, error) {
 	}
 
 	text := s.files[request.TextDocument.Uri]
-	commands := s.completer.Complete(text, request.Position.Line, request.Position.Character)
+
+	completer := *s.completer
+	redis, err := s.connection(request.TextDocument.Uri)
+	if err != nil {
+		log.Printf("error while connecting to Redis for completion: %v", err)
+	} else {
+		completer.Users = redis.Users
+		completer.Keys = redis.Keys
+		if s.connections.CacheEnabled() {
+			completer.Fields = func(key string) []string {
+				ctx, cancel := context.WithTimeout(context.Background(), hoverTimeout)
+				defer cancel()
+
+				fields, err := redis.GetHashFields(ctx, key)
+				if err != nil {
+					log.Printf("error while getting fields for key %v: %v", key, err)
+				}
+
+				return fields
+			}
+		}
+	}
+
+	completer.Functions = func() map[string]string {
+		return s.functionNames(request.TextDocument.Uri)
+	}
+
+	completer.TargetVersion = s.targetVersion(request.TextDocument.Uri)
+
+	complete := completer.Complete
+	if isScript(request.TextDocument.Uri) {
+		complete = completer.CompleteScript
+	}
+
+	completions, typed := complete(text, request.Position.Line, request.Position.Character)
+
+	// the typed text is replaced by the selected item
+	editRange := Range{
+		Start: Position{Line: request.Position.Line, Character: request.Position.Character - len(typed)},
+		End:   request.Position,
+	}
 
 	var items []CompletionItem
-	for _, c := range commands {
-		items = append(items, CompletionItem{Label: c, Kind: Text})
+	for _, c := range completions {
+		item := CompletionItem{
+			Label:      c.Label,
+			Kind:       completionKinds[c.Kind],
+			Detail:     c.Detail,
+			SortText:   fmt.Sprintf("%v_%v", completionOrder[c.Kind], c.Label),
+			FilterText: c.Label,
+			TextEdit:   &TextEdit{Range: editRange, NewText: c.Label},
+		}
+
+		if c.Snippet != "" && s.capabilities.TextDocument.Completion.CompletionItem.SnippetSupport {
+			item.InsertTextFormat = SnippetFormat
+			item.TextEdit.NewText = c.Snippet
+		}
+
+		items = append(items, item)
 	}
 
 	return items, nil
 }
 
+var completionKinds = map[completer.ItemKind]CompletionItemKind{
+	completer.CommandItem:  Keyword,
+	completer.KeyItem:      Value,
+	completer.FieldItem:    Field,
+	completer.OptionItem:   Enum,
+	completer.UserItem:     Variable,
+	completer.VariableItem: Variable,
+	completer.FunctionItem: Function,
+	completer.ConstantItem: Constant,
+}
+
+// items with lower values are shown first
+var completionOrder = map[completer.ItemKind]int{
+	completer.FieldItem:    0,
+	completer.KeyItem:      0,
+	completer.UserItem:     0,
+	completer.VariableItem: 0,
+	completer.FunctionItem: 0,
+	completer.ConstantItem: 0,
+	completer.OptionItem:   1,
+	completer.CommandItem:  2,
+}
+
 func (s Server) handleCompletionResolve(params *json.RawMessage) (interface{}, error) {
 	var request CompletionItem
 	err := json.Unmarshal(*params, &request)
@@ -135,7 +347,13 @@ func (s Server) handleCompletionResolve(params *json.RawMessage) (interface{}, e
 		return nil, err
 	}
 
-	bytes, err := completer.GetDocumentation(request.Label)
+	// the functions of the redis object in Lua scripts are documented as redis.<function>
+	name := request.Label
+	if request.Kind == Function {
+		name = "redis." + name
+	}
+
+	bytes, err := completer.GetDocumentation(name)
 	if err != nil {
 		// if there is no documentation it probably means this is not a keyword
 		// let's swallow this error
@@ -143,7 +361,7 @@ func (s Server) handleCompletionResolve(params *json.RawMessage) (interface{}, e
 		return request, nil
 	}
 
-	request.Documentation = MarkupContent{Kind: Markdown, Value: fmt.Sprintf("### %v \n %v", request.Label, string(bytes))}
+	request.Documentation = MarkupContent{Kind: Markdown, Value: fmt.Sprintf("### %v \n %v", name, string(bytes))}
 
 	return request, nil
 }
@@ -155,6 +373,19 @@ func (s Server) handleWorkspaceExecuteCommand(ctx context.Context, params *json.
 		return nil, err
 	}
 
+	switch request.Command {
+	case switchConnectionCommand:
+		return s.switchConnection(ctx, request.Arguments, conn)
+	case renameKeyCommand:
+		return s.renameKey(ctx, request.Arguments, conn)
+	case runScriptCommand:
+		return s.runScript(ctx, request.Arguments, conn)
+	case loadScriptCommand:
+		return s.loadScript(ctx, request.Arguments, conn)
+	case loadFunctionCommand:
+		return s.loadFunction(ctx, request.Arguments, conn)
+	}
+
 	//tokens :=  // strings.Split(request.Arguments[0].(string), " ")
 
 	tokenizer := token.Tokenizer{}
@@ -168,17 +399,38 @@ func (s Server) handleWorkspaceExecuteCommand(ctx context.Context, params *json.
 
 	statements := ast.New(redisTokens)
 
+	// the second argument is the document the text was taken from, its variables can be used in the text
+	var uri string
+	if len(request.Arguments) > 1 {
+		uri, _ = request.Arguments[1].(string)
+	}
+
+	resolver := s.variableResolver(uri, statements)
+
 	var commands [][]interface{}
 	for _, statement := range statements {
+		if variables.IsDirective(statement) {
+			continue
+		}
+
 		stmtTokens := statement.GetTokens()
 		var new []interface{}
 		for _, t := range stmtTokens {
-			if t.Type() == token.Semicolon || t.Type() == token.Space {
+			if t.Type() == token.Semicolon || t.Type() == token.Space || t.Type() == token.Newline || t.Type() == token.Comment {
 				continue
 			}
 
+			if t.Type() == token.Illegal {
+				message := ShowMessageParams{
+					Message: fmt.Sprintf("Invalid argument %v: %v", t.String(), token.IllegalReason(t.String())),
+					Type: Error,
+				}
+
+				return nil, conn.Notify(ctx, "window/showMessage", message)
+			}
+
 			if t.Type() == token.MultiKeyword {
-				split :=  strings.Split(t.String(), " ")
+				split := strings.Fields(t.String())
 				for _, s := range split {
 					new = append(new, s)
 				}
@@ -186,14 +438,52 @@ func (s Server) handleWorkspaceExecuteCommand(ctx context.Context, params *json.
 				continue
 			}
 
-			new = append(new, t.String())
+			// Redis receives the value without the quotes and with the escape sequences replaced
+			value, _ := token.Unquote(t.String())
+
+			if !strings.HasPrefix(t.String(), "'") {
+				var unresolved []string
+				value, unresolved = resolver.Resolve(value, statement.Line())
+				if len(unresolved) > 0 {
+					message := ShowMessageParams{
+						Message: fmt.Sprintf("Variables not defined: %v", strings.Join(unresolved, ", ")),
+						Type: Error,
+					}
+
+					return nil, conn.Notify(ctx, "window/showMessage", message)
+				}
+			}
+
+			new = append(new, value)
+		}
+
+		if len(new) == 0 {
+			continue
 		}
 
 		commands = append(commands, new)
 	}
 
+	// in safe mode nothing is executed when one of the commands is dangerous
+	for _, command := range commands {
+		if blocked, err := s.blockedInSafeMode(ctx, conn, command); blocked {
+			return nil, err
+		}
+	}
+
+	redisClient, err := s.connection(uri)
+	if err != nil {
+		return nil, conn.Notify(ctx, "window/showMessage", ShowMessageParams{Message: err.Error(), Type: Error})
+	}
+
 	for _, command := range commands {
-		val, err := s.redis.ExecuteCommand(ctx, command)
+		val, err := redisClient.ExecuteCommand(ctx, command)
+
+		// the command may have loaded or deleted libraries (e.g. FUNCTION LOAD or FUNCTION FLUSH)
+		if name, _ := command[0].(string); strings.EqualFold(name, "FUNCTION") {
+			s.clearFunctions()
+		}
+
 		if err != nil {
 			if err == redis.Nil {
 				logMessage := LogMessageParams{
@@ -236,14 +526,17 @@ func (s Server) handleWorkspaceExecuteCommand(ctx context.Context, params *json.
 	return nil, nil
 }
 
-func (s Server) handleWorkspaceDidChangeConfiguration(params *json.RawMessage) (interface{}, error) {
+func (s Server) handleWorkspaceDidChangeConfiguration(ctx context.Context, params *json.RawMessage, conn *jsonrpc2.Conn) (interface{}, error) {
 	var request DidChangeConfigurationParams
 	err := json.Unmarshal(*params, &request)
 	if err != nil {
 		return nil, err
 	}
 
-	log.Println(request)
+	err = s.applySettings(request.Settings.Redis)
+	if err != nil {
+		return nil, err
+	}
 
-	return nil, nil
+	return nil, s.settingsChanged(ctx, conn)
 }
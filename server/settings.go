@@ -0,0 +1,218 @@
+package server
+
+import (
+	"encoding/json"
+	"log"
+	"strings"
+
+	"github.com/fagnercarvalho/redis-lsp/analysis"
+	"github.com/fagnercarvalho/redis-lsp/client"
+	"github.com/fagnercarvalho/redis-lsp/completer"
+)
+
+// Settings are the options sent by the client in the "redis" section of the configuration
+// or as initialization options and the options in the workspace configuration file.
+type Settings struct {
+	Completion CompletionSettings `json:"completion"`
+	Variables  VariablesSettings  `json:"variables"`
+
+	// Connections are added to the connection created from the command line flags, which is called "default".
+	Connections       []client.Profile `json:"connections"`
+	DefaultConnection string           `json:"defaultConnection"`
+
+	Safety      SafetySettings      `json:"safety"`
+	Formatting  FormattingSettings  `json:"formatting"`
+	Diagnostics DiagnosticsSettings `json:"diagnostics"`
+	Lint        LintSettings        `json:"lint"`
+
+	// KeyDelimiter separates the parts of a key name (e.g. "user:42:session").
+	KeyDelimiter string `json:"keyDelimiter"`
+
+	// TargetVersion is the Redis version the scripts are written for (e.g. "6.2").
+	// When it is empty the version of the connected server is used.
+	TargetVersion string `json:"targetVersion"`
+}
+
+type CompletionSettings struct {
+	// Case is how commands and options are completed: "upper", "lower" or "as-typed".
+	Case completer.LetterCase `json:"case"`
+}
+
+type VariablesSettings struct {
+	// EnvFile is the file with NAME=value variables, relative to the workspace root.
+	EnvFile string `json:"envFile"`
+}
+
+type SafetySettings struct {
+	// SafeMode prevents the dangerous commands from being executed.
+	SafeMode bool `json:"safeMode"`
+
+	// DangerousCommands are commands or subcommands (e.g. "CONFIG SET") blocked in safe mode.
+	DangerousCommands []string `json:"dangerousCommands"`
+}
+
+type FormattingSettings struct {
+	// Case is how commands and options are written: "upper", "lower" or "as-typed" to keep them as they are.
+	Case completer.LetterCase `json:"case"`
+}
+
+type DiagnosticsSettings struct {
+	// Disabled are the codes of the diagnostics that are not reported (e.g. "unresolved-variable").
+	Disabled []string `json:"disabled"`
+}
+
+type LintSettings struct {
+	// Rules are the severities of the lint rules by code: "off", "hint", "info", "warning" or "error"
+	// (e.g. {"keys-wildcard": "error"}), rules that are not configured are warnings.
+	Rules map[string]string `json:"rules"`
+
+	// LiveCardinality fetches the number of elements of the keys from the server
+	// so the rules about big keys are only reported for keys with at least LargeKey elements.
+	LiveCardinality bool  `json:"liveCardinality"`
+	LargeKey        int64 `json:"largeKey"`
+}
+
+// settingsSources are the raw settings that are merged, in order, on top of the default settings.
+type settingsSources struct {
+	// workspace are the configuration files found in the workspace roots.
+	workspace []json.RawMessage
+
+	// client are the settings sent by the client, they take precedence over the configuration files.
+	client json.RawMessage
+}
+
+func defaultSettings() Settings {
+	return Settings{
+		Completion: CompletionSettings{Case: completer.AsTyped},
+		Variables:  VariablesSettings{EnvFile: ".env"},
+		Safety: SafetySettings{
+			DangerousCommands: []string{
+				"FLUSHALL", "FLUSHDB", "KEYS", "SHUTDOWN", "DEBUG", "CONFIG SET", "CONFIG RESETSTAT",
+				"SCRIPT FLUSH", "FUNCTION FLUSH", "CLUSTER RESET", "FAILOVER", "REPLICAOF", "SLAVEOF",
+			},
+		},
+		Formatting:   FormattingSettings{Case: completer.UpperCase},
+		Lint:         LintSettings{LargeKey: 1000},
+		KeyDelimiter: ":",
+	}
+}
+
+// applySettings replaces the settings sent by the client, options that are not sent keep the values
+// from the workspace configuration file or the default ones.
+func (s Server) applySettings(raw json.RawMessage) error {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil
+	}
+
+	s.sources.client = raw
+
+	return s.reloadSettings()
+}
+
+// reloadSettings merges the default settings with the workspace configuration files and the client settings.
+func (s Server) reloadSettings() error {
+	settings := defaultSettings()
+	for _, raw := range append(s.sources.workspace, s.sources.client) {
+		if len(raw) == 0 || string(raw) == "null" {
+			continue
+		}
+
+		err := json.Unmarshal(raw, &settings)
+		if err != nil {
+			return err
+		}
+	}
+
+	*s.settings = settings
+	s.completer.Case = settings.Completion.Case
+	settings.Lint.logUnknownLevels()
+
+	profiles := []client.Profile{*s.defaultProfile}
+	for _, p := range settings.Connections {
+		if p.Name == defaultConnection {
+			profiles[0] = p
+			continue
+		}
+
+		profiles = append(profiles, p)
+	}
+
+	s.connections.SetProfiles(profiles)
+
+	if settings.DefaultConnection != "" {
+		return s.connections.Switch(settings.DefaultConnection)
+	}
+
+	return nil
+}
+
+// blocked returns the dangerous command that matches the beginning of the command.
+func (s SafetySettings) blocked(command []interface{}) (string, bool) {
+	if !s.SafeMode {
+		return "", false
+	}
+
+	for _, dangerous := range s.DangerousCommands {
+		words := strings.Fields(dangerous)
+		if len(words) == 0 || len(words) > len(command) {
+			continue
+		}
+
+		matches := true
+		for i, w := range words {
+			value, ok := command[i].(string)
+			if !ok || !strings.EqualFold(value, w) {
+				matches = false
+				break
+			}
+		}
+
+		if matches {
+			return strings.ToUpper(dangerous), true
+		}
+	}
+
+	return "", false
+}
+
+// diagnosticEnabled reports whether diagnostics with the code should be reported.
+func (s DiagnosticsSettings) diagnosticEnabled(code string) bool {
+	for _, d := range s.Disabled {
+		if d == code {
+			return false
+		}
+	}
+
+	return true
+}
+
+var lintSeverities = map[string]analysis.Severity{
+	"error":   analysis.Error,
+	"warning": analysis.Warning,
+	"info":    analysis.Information,
+	"hint":    analysis.Hint,
+}
+
+// severity returns the severity configured for the lint rule, ok is false when the rule is turned off.
+// Rules that are not configured or have an unknown level (e.g. "warn") are warnings.
+func (s LintSettings) severity(code string) (analysis.Severity, bool) {
+	level := strings.ToLower(s.Rules[code])
+	if level == "off" {
+		return 0, false
+	}
+
+	if severity, ok := lintSeverities[level]; ok {
+		return severity, true
+	}
+
+	return analysis.Warning, true
+}
+
+// logUnknownLevels logs the lint rules configured with a level that is not "off" or a severity.
+func (s LintSettings) logUnknownLevels() {
+	for code, level := range s.Rules {
+		if _, ok := lintSeverities[strings.ToLower(level)]; !ok && !strings.EqualFold(level, "off") {
+			log.Printf("unknown level %q for lint rule %v, using warning", level, code)
+		}
+	}
+}
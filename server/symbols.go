@@ -0,0 +1,219 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/fagnercarvalho/redis-lsp/ast"
+	"github.com/fagnercarvalho/redis-lsp/completer"
+	"github.com/fagnercarvalho/redis-lsp/token"
+	"github.com/fagnercarvalho/redis-lsp/variables"
+)
+
+// handleDocumentSymbol returns the outline of the document: regions and transactions
+// with the statements inside of them as children.
+func (s Server) handleDocumentSymbol(params *json.RawMessage) (interface{}, error) {
+	var request DocumentSymbolParams
+	err := json.Unmarshal(*params, &request)
+	if err != nil {
+		return nil, err
+	}
+
+	if isScript(request.TextDocument.Uri) {
+		return nil, nil
+	}
+
+	text := s.files[request.TextDocument.Uri]
+	statements := ast.Parse(text)
+	lines := strings.Split(text, "\n")
+
+	var containers []DocumentSymbol
+	for _, r := range ast.GetRegions(statements) {
+		name := r.Name
+		if name == "" {
+			name = "region"
+		}
+
+		containers = append(containers, blockSymbol(lines, r, name, NamespaceSymbol))
+	}
+
+	for _, t := range ast.GetTransactions(statements) {
+		containers = append(containers, blockSymbol(lines, t, t.Name, ObjectSymbol))
+	}
+
+	// outer blocks are added first so the inner ones become their children
+	sort.SliceStable(containers, func(i, j int) bool {
+		if containers[i].Range.Start.Line != containers[j].Range.Start.Line {
+			return containers[i].Range.Start.Line < containers[j].Range.Start.Line
+		}
+
+		return containers[i].Range.End.Line > containers[j].Range.End.Line
+	})
+
+	symbols := []DocumentSymbol{}
+	for _, c := range containers {
+		symbols = addSymbol(symbols, c)
+	}
+
+	for _, statement := range statements {
+		symbol, ok := statementSymbol(statement)
+		if ok {
+			symbols = addSymbol(symbols, symbol)
+		}
+	}
+
+	sortSymbols(symbols)
+
+	return symbols, nil
+}
+
+// sortSymbols sorts the symbols and their children by position since blocks are added before statements.
+func sortSymbols(symbols []DocumentSymbol) {
+	sort.SliceStable(symbols, func(i, j int) bool {
+		return positionBefore(symbols[i].Range.Start, symbols[j].Range.Start)
+	})
+
+	for _, s := range symbols {
+		sortSymbols(s.Children)
+	}
+}
+
+// addSymbol adds the symbol as a child of the symbol that contains it or to the list when there is none.
+func addSymbol(symbols []DocumentSymbol, symbol DocumentSymbol) []DocumentSymbol {
+	for i := range symbols {
+		if contains(symbols[i].Range, symbol.Range) {
+			symbols[i].Children = addSymbol(symbols[i].Children, symbol)
+			return symbols
+		}
+	}
+
+	return append(symbols, symbol)
+}
+
+func contains(outer Range, inner Range) bool {
+	return !positionBefore(inner.Start, outer.Start) && !positionBefore(outer.End, inner.End)
+}
+
+func positionBefore(a Position, b Position) bool {
+	return a.Line < b.Line || (a.Line == b.Line && a.Character < b.Character)
+}
+
+// blockSymbol returns the symbol of a region or transaction, its name is selected in the first line.
+func blockSymbol(lines []string, r ast.Region, name string, kind SymbolKind) DocumentSymbol {
+	end := 0
+	if r.EndLine < len(lines) {
+		end = len(strings.TrimSuffix(lines[r.EndLine], "\r"))
+	}
+
+	start := 0
+	if r.StartLine < len(lines) {
+		start = len(lines[r.StartLine]) - len(strings.TrimLeft(lines[r.StartLine], " \t"))
+	}
+
+	return DocumentSymbol{
+		Name:           name,
+		Kind:           kind,
+		Range:          Range{Start: Position{Line: r.StartLine, Character: 0}, End: Position{Line: r.EndLine, Character: end}},
+		SelectionRange: Range{Start: Position{Line: r.StartLine, Character: start}, End: Position{Line: r.StartLine, Character: start}},
+	}
+}
+
+// statementSymbol returns a symbol named after the command and its first key (e.g. "HSET user:1"),
+// variable definitions are named after the variable.
+func statementSymbol(statement ast.TokenList) (DocumentSymbol, bool) {
+	command := statement.GetCommand()
+	if command == nil {
+		return DocumentSymbol{}, false
+	}
+
+	name := ast.CommandName(statement)
+	arguments := statement.GetArguments()
+	symbol := DocumentSymbol{
+		Name:           name,
+		Detail:         statementText(statement),
+		Kind:           FunctionSymbol,
+		Range:          statementRange(statement),
+		SelectionRange: nodeRange(command),
+	}
+
+	if variables.IsDirective(statement) {
+		if len(arguments) == 0 {
+			return DocumentSymbol{}, false
+		}
+
+		definitions := variables.Definitions([]ast.TokenList{statement})
+		if len(definitions) == 1 {
+			symbol.Name = definitions[0].Name
+		} else {
+			symbol.Name = arguments[0].String()
+		}
+
+		symbol.Kind = VariableSymbol
+		symbol.SelectionRange = nodeRange(arguments[0])
+
+		return symbol, true
+	}
+
+	var values []string
+	for _, a := range arguments {
+		values = append(values, a.String())
+	}
+
+	keys := completer.GetKeyIndexes(name, values)
+	if len(keys) > 0 && keys[0] < len(arguments) {
+		key, err := token.Unquote(values[keys[0]])
+		if err == nil {
+			symbol.Name = fmt.Sprintf("%v %v", name, key)
+		}
+	}
+
+	return symbol, true
+}
+
+// statementRange returns the range from the command to the last argument or comment of the statement.
+func statementRange(statement ast.TokenList) Range {
+	var first, last ast.Node
+	for _, t := range statement.GetTokens() {
+		if t.Type() == token.Space || t.Type() == token.Newline || t.Type() == token.Semicolon {
+			continue
+		}
+
+		if first == nil {
+			first = t
+		}
+
+		last = t
+	}
+
+	if first == nil {
+		return Range{Start: Position{Line: statement.Line()}, End: Position{Line: statement.Line()}}
+	}
+
+	return Range{
+		Start: Position{Line: first.Line(), Character: first.LineStart()},
+		End:   Position{Line: ast.EndLine(last), Character: last.LineEnd() + 1},
+	}
+}
+
+func nodeRange(node ast.Node) Range {
+	return Range{
+		Start: Position{Line: node.Line(), Character: node.LineStart()},
+		End:   Position{Line: ast.EndLine(node), Character: node.LineEnd() + 1},
+	}
+}
+
+// statementText returns the statement without the comment, the separator and the line break.
+func statementText(statement ast.TokenList) string {
+	var words []string
+	for _, t := range statement.GetTokens() {
+		if t.Type() == token.Space || t.Type() == token.Newline || t.Type() == token.Semicolon || t.Type() == token.Comment {
+			continue
+		}
+
+		words = append(words, t.String())
+	}
+
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,89 @@
+package server
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/fagnercarvalho/redis-lsp/ast"
+	"github.com/fagnercarvalho/redis-lsp/variables"
+)
+
+// variableResolver returns the resolver for the variables used in the statements.
+// When the statements were taken from a document the variables defined in the whole document can be used as well.
+func (s Server) variableResolver(uri string, statements []ast.TokenList) variables.Resolver {
+	var definitions []variables.Variable
+	if text, ok := s.files[uri]; ok {
+		for _, v := range variables.Definitions(ast.Parse(text)) {
+			v.Line = -1
+			definitions = append(definitions, v)
+		}
+	}
+
+	definitions = append(definitions, variables.Definitions(statements)...)
+
+	return variables.Resolver{Definitions: definitions, EnvFile: s.envFile()}
+}
+
+// envFile returns the variables of the env file in the first workspace root that has it.
+func (s Server) envFile() map[string]string {
+	path := s.settings.Variables.EnvFile
+	if path == "" {
+		return nil
+	}
+
+	var paths []string
+	if filepath.IsAbs(path) {
+		paths = append(paths, path)
+	} else {
+		for _, root := range *s.roots {
+			paths = append(paths, filepath.Join(root, path))
+		}
+	}
+
+	for _, p := range paths {
+		vars, err := variables.LoadEnvFile(p)
+		if err == nil {
+			return vars
+		}
+
+		if !os.IsNotExist(err) {
+			log.Printf("error while loading env file %v: %v", p, err)
+		}
+	}
+
+	return nil
+}
+
+// variableHover returns the value of the ${NAME} placeholder under the position, if any.
+func variableHover(resolver variables.Resolver, line int, position int, node ast.Node) *Hover {
+	for _, r := range variables.References(node.String()) {
+		referenceLine, start := ast.OffsetPosition(node, r.Start)
+		end := start + r.End - r.Start
+		if referenceLine != line || position < start || position >= end {
+			continue
+		}
+
+		value := fmt.Sprintf("### ${%v} \n Variable is not defined.", r.Name)
+		if v, ok := resolver.Lookup(r.Name, line); ok {
+			source := string(v.Source)
+			if v.Source == variables.File {
+				source = fmt.Sprintf("line %v", v.Line+1)
+			}
+
+			resolved, _ := resolver.Resolve("${"+r.Name+"}", line)
+			value = fmt.Sprintf("### ${%v} \n```\n%v\n```\nDefined in %v.", r.Name, resolved, source)
+		}
+
+		return &Hover{
+			Contents: MarkupContent{Kind: Markdown, Value: value},
+			Range: &Range{
+				Start: Position{Line: line, Character: start},
+				End:   Position{Line: line, Character: end},
+			},
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,59 @@
+package server
+
+import (
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// redisExtension is the extension of the Redis script files searched in the workspace.
+const redisExtension = ".redis"
+
+// workspaceDocuments returns the text of the files with the extension in the workspace roots by URI.
+// Open documents are used instead of the files on disk since they may have unsaved changes.
+func (s Server) workspaceDocuments(extension string) map[string]string {
+	documents := map[string]string{}
+	open := map[string]bool{}
+	for uri, text := range s.files {
+		if strings.EqualFold(filepath.Ext(uriToPath(uri)), extension) {
+			documents[uri] = text
+		}
+
+		open[filepath.Clean(uriToPath(uri))] = true
+	}
+
+	for _, root := range *s.roots {
+		err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return nil
+			}
+
+			// hidden folders (e.g. .git) and dependencies do not have scripts of the workspace
+			if info.IsDir() && path != root && (strings.HasPrefix(info.Name(), ".") || info.Name() == "node_modules") {
+				return filepath.SkipDir
+			}
+
+			if info.IsDir() || !strings.EqualFold(filepath.Ext(path), extension) || open[filepath.Clean(path)] {
+				return nil
+			}
+
+			data, err := ioutil.ReadFile(path)
+			if err != nil {
+				log.Printf("error while reading %v: %v", path, err)
+				return nil
+			}
+
+			documents[pathToURI(path)] = string(data)
+
+			return nil
+		})
+
+		if err != nil {
+			log.Printf("error while searching files in %v: %v", root, err)
+		}
+	}
+
+	return documents
+}
@@ -0,0 +1,344 @@
+package server
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/fagnercarvalho/redis-lsp/ast"
+	"github.com/fagnercarvalho/redis-lsp/lua"
+	"github.com/fagnercarvalho/redis-lsp/token"
+)
+
+// maxWorkspaceSymbols limits the symbols returned since a query such as "user" may match every key.
+const maxWorkspaceSymbols = 500
+
+// handleWorkspaceSymbol searches the keys and scripts used in the workspace Redis files, the keys cached from the active
+// connection and the scripts and functions loaded in it.
+// Since only documents can be opened, a cached key is located at the KEYS and SCAN patterns matching it and a loaded
+// function at its FCALL statements, or at the start of the first workspace Redis file when nothing uses them.
+func (s Server) handleWorkspaceSymbol(params *json.RawMessage) (interface{}, error) {
+	var request WorkspaceSymbolParams
+	err := json.Unmarshal(*params, &request)
+	if err != nil {
+		return nil, err
+	}
+
+	query := strings.ToLower(request.Query)
+	matches := func(name string) bool {
+		return strings.Contains(strings.ToLower(name), query)
+	}
+
+	documents := s.workspaceDocuments(redisExtension)
+
+	symbols := []SymbolInformation{}
+	usedKeys := map[string]bool{}
+	patterns := map[string][]Location{}
+	calls := map[string][]Location{}
+	for _, uri := range sortedURIs(documents) {
+		statements := ast.Parse(documents[uri])
+		for _, o := range keyOccurrences(statements) {
+			usedKeys[o.Key] = true
+			if matches(o.Key) {
+				symbols = append(symbols, SymbolInformation{
+					Name:          o.Key,
+					Kind:          KeySymbol,
+					Location:      Location{Uri: uri, Range: nodeRange(o.Node)},
+					ContainerName: o.Command,
+				})
+			}
+		}
+
+		for _, script := range scriptOccurrences(statements) {
+			if matches(script.SHA) {
+				symbols = append(symbols, SymbolInformation{
+					Name:          script.SHA,
+					Kind:          FunctionSymbol,
+					Location:      Location{Uri: uri, Range: nodeRange(script.Node)},
+					ContainerName: script.Command,
+				})
+			}
+		}
+
+		for _, p := range patternOccurrences(statements) {
+			patterns[p.Pattern] = append(patterns[p.Pattern], Location{Uri: uri, Range: nodeRange(p.Node)})
+		}
+
+		for name, nodes := range functionCalls(statements) {
+			for _, n := range nodes {
+				calls[name] = append(calls[name], Location{Uri: uri, Range: nodeRange(n)})
+			}
+		}
+	}
+
+	// script files are named by the SHA1 they have when loaded and libraries by the functions they register
+	scripts := s.workspaceDocuments(luaExtension)
+	shas := map[string]bool{}
+	registered := map[string]bool{}
+	for _, uri := range sortedURIs(scripts) {
+		if library, ok := lua.ParseLibrary(scripts[uri]); ok {
+			for _, f := range library.Functions {
+				registered[f.Name] = true
+				if matches(f.Name) {
+					symbols = append(symbols, SymbolInformation{
+						Name:          f.Name,
+						Kind:          FunctionSymbol,
+						Location:      Location{Uri: uri, Range: Range{Start: textPosition(scripts[uri], f.Start), End: textPosition(scripts[uri], f.End)}},
+						ContainerName: library.Name,
+					})
+				}
+			}
+
+			continue
+		}
+
+		sha := scriptSHA(scripts[uri])
+		shas[sha] = true
+		if matches(sha) {
+			symbols = append(symbols, SymbolInformation{
+				Name:          sha,
+				Kind:          FunctionSymbol,
+				Location:      Location{Uri: uri},
+				ContainerName: filepath.Base(uriToPath(uri)),
+			})
+		}
+	}
+
+	// the script files may have been changed after they were loaded with SCRIPT LOAD
+	for _, sha := range sortedURIs(s.scripts) {
+		if !shas[sha] && matches(sha) {
+			symbols = append(symbols, SymbolInformation{
+				Name:          sha,
+				Kind:          FunctionSymbol,
+				Location:      Location{Uri: s.scripts[sha]},
+				ContainerName: filepath.Base(uriToPath(s.scripts[sha])),
+			})
+		}
+	}
+
+	var fallback []Location
+	if uris := sortedURIs(documents); len(uris) > 0 {
+		fallback = []Location{{Uri: uris[0]}}
+	}
+
+	var sortedPatterns []string
+	for pattern := range patterns {
+		sortedPatterns = append(sortedPatterns, pattern)
+	}
+
+	sort.Strings(sortedPatterns)
+
+	connection := s.connectionProfile("").Name
+	if redis, err := s.connection(""); err == nil {
+		for _, key := range redis.Keys {
+			if key == "" || usedKeys[key] || !matches(key) {
+				continue
+			}
+
+			var locations []Location
+			for _, pattern := range sortedPatterns {
+				if matchesPattern(pattern, key) {
+					locations = append(locations, patterns[pattern]...)
+				}
+			}
+
+			if len(locations) == 0 {
+				locations = fallback
+			}
+
+			for _, l := range locations {
+				symbols = append(symbols, SymbolInformation{Name: key, Kind: KeySymbol, Location: l, ContainerName: connection})
+			}
+		}
+	}
+
+	loaded := s.functionNames("")
+	for _, name := range sortedURIs(loaded) {
+		if registered[name] || !matches(name) {
+			continue
+		}
+
+		locations := calls[name]
+		if len(locations) == 0 {
+			locations = fallback
+		}
+
+		for _, l := range locations {
+			symbols = append(symbols, SymbolInformation{Name: name, Kind: FunctionSymbol, Location: l, ContainerName: loaded[name]})
+		}
+	}
+
+	if len(symbols) > maxWorkspaceSymbols {
+		symbols = symbols[:maxWorkspaceSymbols]
+	}
+
+	return symbols, nil
+}
+
+// patternOccurrence is a key pattern of a KEYS or SCAN statement.
+type patternOccurrence struct {
+	Pattern string
+	Node    ast.Node
+}
+
+// patternOccurrences returns the patterns of the KEYS statements and of the MATCH option of the SCAN statements.
+func patternOccurrences(statements []ast.TokenList) []patternOccurrence {
+	var occurrences []patternOccurrence
+	for _, s := range statements {
+		command := ast.CommandName(s)
+		arguments := s.GetArguments()
+
+		for i, a := range arguments {
+			isPattern := command == "KEYS" && i == 0
+			if command == "SCAN" && i > 0 {
+				isPattern = strings.EqualFold(arguments[i-1].String(), "MATCH")
+			}
+
+			if !isPattern {
+				continue
+			}
+
+			pattern, err := token.Unquote(a.String())
+			if err != nil {
+				continue
+			}
+
+			occurrences = append(occurrences, patternOccurrence{Pattern: pattern, Node: a})
+		}
+	}
+
+	return occurrences
+}
+
+// functionCalls returns the function name arguments of the FCALL statements by name.
+func functionCalls(statements []ast.TokenList) map[string][]ast.Node {
+	calls := map[string][]ast.Node{}
+	for _, s := range statements {
+		switch ast.CommandName(s) {
+		case "FCALL", "FCALL_RO":
+		default:
+			continue
+		}
+
+		arguments := s.GetArguments()
+		if len(arguments) == 0 {
+			continue
+		}
+
+		name, err := token.Unquote(arguments[0].String())
+		if err != nil {
+			continue
+		}
+
+		calls[name] = append(calls[name], arguments[0])
+	}
+
+	return calls
+}
+
+// matchesPattern reports whether the key matches the glob-style pattern of KEYS and SCAN,
+// where * matches any characters, ? one character, [abc] or [a-z] one of the characters and \ escapes a character.
+func matchesPattern(pattern string, key string) bool {
+	for len(pattern) > 0 {
+		switch pattern[0] {
+		case '*':
+			for i := len(key); i >= 0; i-- {
+				if matchesPattern(pattern[1:], key[i:]) {
+					return true
+				}
+			}
+
+			return false
+		case '?':
+			if key == "" {
+				return false
+			}
+		case '[':
+			end := strings.IndexByte(pattern[1:], ']')
+			if end == -1 || key == "" {
+				return false
+			}
+
+			class := pattern[1 : end+1]
+			negated := strings.HasPrefix(class, "^")
+			if negated {
+				class = class[1:]
+			}
+
+			if inClass(class, key[0]) == negated {
+				return false
+			}
+
+			pattern = pattern[end+1:]
+		case '\\':
+			if len(pattern) > 1 {
+				pattern = pattern[1:]
+			}
+
+			fallthrough
+		default:
+			if key == "" || key[0] != pattern[0] {
+				return false
+			}
+		}
+
+		pattern = pattern[1:]
+		key = key[1:]
+	}
+
+	return key == ""
+}
+
+// inClass reports whether the character is in a class of a pattern such as "abc" or "a-z".
+func inClass(class string, c byte) bool {
+	for i := 0; i < len(class); i++ {
+		if i+2 < len(class) && class[i+1] == '-' {
+			if class[i] <= c && c <= class[i+2] {
+				return true
+			}
+
+			i += 2
+			continue
+		}
+
+		if class[i] == c {
+			return true
+		}
+	}
+
+	return false
+}
+
+// scriptOccurrence is a Lua script loaded by a statement or the SHA1 of a script called by EVALSHA.
+type scriptOccurrence struct {
+	SHA     string
+	Node    ast.Node
+	Command string
+}
+
+// scriptOccurrences returns the scripts of EVAL and SCRIPT LOAD statements and the scripts called by EVALSHA.
+func scriptOccurrences(statements []ast.TokenList) []scriptOccurrence {
+	var occurrences []scriptOccurrence
+	for _, s := range statements {
+		command := ast.CommandName(s)
+		arguments := s.GetArguments()
+		if len(arguments) == 0 {
+			continue
+		}
+
+		value, err := token.Unquote(arguments[0].String())
+		if err != nil {
+			continue
+		}
+
+		switch command {
+		case "EVAL", "EVAL_RO", "SCRIPT LOAD":
+			occurrences = append(occurrences, scriptOccurrence{SHA: scriptSHA(value), Node: arguments[0], Command: command})
+		case "EVALSHA", "EVALSHA_RO":
+			occurrences = append(occurrences, scriptOccurrence{SHA: strings.ToLower(value), Node: arguments[0], Command: command})
+		}
+	}
+
+	return occurrences
+}
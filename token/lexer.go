@@ -1,5 +1,9 @@
 package token
 
+import (
+	"strings"
+)
+
 type Type int
 
 //go:generate stringer -type Type lexer.go
@@ -14,47 +18,65 @@ const (
 	MultiKeyword
 
 	Unknown
+	Illegal
+	Comment
 )
 
 var matches = map[string]Type{
 	"ACL":                   Keyword,
 	"ADDSLOTS":              Keyword,
+	"ADDSLOTSRANGE":         Keyword,
 	"APPEND":                Keyword,
+	"ASKING":                Keyword,
 	"AUTH":                  Keyword,
 	"BGREWRITEAOF":          Keyword,
 	"BGSAVE":                Keyword,
 	"BITCOUNT":              Keyword,
 	"BITFIELD":              Keyword,
+	"BITFIELD_RO":           Keyword,
 	"BITOP":                 Keyword,
 	"BITPOS":                Keyword,
 	"BLMOVE":                Keyword,
+	"BLMPOP":                Keyword,
 	"BLPOP":                 Keyword,
 	"BRPOP":                 Keyword,
 	"BRPOPLPUSH":            Keyword,
 	"BUMPEPOCH":             Keyword,
+	"BZMPOP":                Keyword,
 	"BZPOPMAX":              Keyword,
 	"BZPOPMIN":              Keyword,
 	"CACHING":               Keyword,
 	"CAT":                   Keyword,
+	"CHANNELS":              Keyword,
 	"CLIENT":                Keyword,
 	"CLUSTER":               Keyword,
 	"COMMAND":               Keyword,
 	"CONFIG":                Keyword,
+	"CONSUMERS":             Keyword,
 	"COPY":                  Keyword,
 	"COUNT":                 Keyword,
 	"COUNT-FAILURE-REPORTS": Keyword,
 	"COUNTKEYSINSLOT":       Keyword,
+	"CREATE":                Keyword,
+	"CREATECONSUMER":        Keyword,
 	"DBSIZE":                Keyword,
 	"DEBUG":                 Keyword,
 	"DECR":                  Keyword,
 	"DECRBY":                Keyword,
 	"DEL":                   Keyword,
+	"DELCONSUMER":           Keyword,
+	"DELETE":                Keyword,
 	"DELSLOTS":              Keyword,
+	"DELSLOTSRANGE":         Keyword,
 	"DELUSER":               Keyword,
+	"DESTROY":               Keyword,
 	"DISCARD":               Keyword,
+	"DOCS":                  Keyword,
 	"DOCTOR":                Keyword,
+	"DRYRUN":                Keyword,
 	"DUMP":                  Keyword,
 	"ECHO":                  Keyword,
+	"ENCODING":              Keyword,
 	"EVAL":                  Keyword,
 	"EVALSHA":               Keyword,
 	"EVALSHA_RO":            Keyword,
@@ -65,11 +87,15 @@ var matches = map[string]Type{
 	"EXPIREAT":              Keyword,
 	"EXPIRETIME":            Keyword,
 	"FAILOVER":              Keyword,
+	"FCALL":                 Keyword,
+	"FCALL_RO":              Keyword,
 	"FLUSH":                 Keyword,
 	"FLUSHALL":              Keyword,
 	"FLUSHDB":               Keyword,
 	"FLUSHSLOTS":            Keyword,
 	"FORGET":                Keyword,
+	"FREQ":                  Keyword,
+	"FUNCTION":              Keyword,
 	"GENPASS":               Keyword,
 	"GEOADD":                Keyword,
 	"GEODIST":               Keyword,
@@ -77,6 +103,8 @@ var matches = map[string]Type{
 	"GEOPOS":                Keyword,
 	"GEORADIUS":             Keyword,
 	"GEORADIUSBYMEMBER":     Keyword,
+	"GEORADIUSBYMEMBER_RO":  Keyword,
+	"GEORADIUS_RO":          Keyword,
 	"GEOSEARCH":             Keyword,
 	"GEOSEARCHSTORE":        Keyword,
 	"GET":                   Keyword,
@@ -84,6 +112,7 @@ var matches = map[string]Type{
 	"GETDEL":                Keyword,
 	"GETEX":                 Keyword,
 	"GETKEYS":               Keyword,
+	"GETKEYSANDFLAGS":       Keyword,
 	"GETKEYSINSLOT":         Keyword,
 	"GETNAME":               Keyword,
 	"GETRANGE":              Keyword,
@@ -91,6 +120,7 @@ var matches = map[string]Type{
 	"GETSET":                Keyword,
 	"GETUSER":               Keyword,
 	"GRAPH":                 Keyword,
+	"GROUPS":                Keyword,
 	"HDEL":                  Keyword,
 	"HELLO":                 Keyword,
 	"HELP":                  Keyword,
@@ -99,6 +129,7 @@ var matches = map[string]Type{
 	"HGETALL":               Keyword,
 	"HINCRBY":               Keyword,
 	"HINCRBYFLOAT":          Keyword,
+	"HISTOGRAM":             Keyword,
 	"HISTORY":               Keyword,
 	"HKEYS":                 Keyword,
 	"HLEN":                  Keyword,
@@ -111,6 +142,7 @@ var matches = map[string]Type{
 	"HSTRLEN":               Keyword,
 	"HVALS":                 Keyword,
 	"ID":                    Keyword,
+	"IDLETIME":              Keyword,
 	"INCR":                  Keyword,
 	"INCRBY":                Keyword,
 	"INCRBYFLOAT":           Keyword,
@@ -121,12 +153,17 @@ var matches = map[string]Type{
 	"LASTSAVE":              Keyword,
 	"LATENCY":               Keyword,
 	"LATEST":                Keyword,
+	"LCS":                   Keyword,
+	"LEN":                   Keyword,
 	"LINDEX":                Keyword,
+	"LINKS":                 Keyword,
 	"LINSERT":               Keyword,
 	"LIST":                  Keyword,
 	"LLEN":                  Keyword,
 	"LMOVE":                 Keyword,
+	"LMPOP":                 Keyword,
 	"LOAD":                  Keyword,
+	"LOADEX":                Keyword,
 	"LOG":                   Keyword,
 	"LOLWUT":                Keyword,
 	"LPOP":                  Keyword,
@@ -149,7 +186,12 @@ var matches = map[string]Type{
 	"MSETNX":                Keyword,
 	"MULTI":                 Keyword,
 	"MYID":                  Keyword,
+	"MYSHARDID":             Keyword,
+	"NO-EVICT":              Keyword,
+	"NO-TOUCH":              Keyword,
 	"NODES":                 Keyword,
+	"NUMPAT":                Keyword,
+	"NUMSUB":                Keyword,
 	"OBJECT":                Keyword,
 	"PAUSE":                 Keyword,
 	"PERSIST":               Keyword,
@@ -172,6 +214,7 @@ var matches = map[string]Type{
 	"RANDOMKEY":             Keyword,
 	"READONLY":              Keyword,
 	"READWRITE":             Keyword,
+	"REFCOUNT":              Keyword,
 	"RENAME":                Keyword,
 	"RENAMENX":              Keyword,
 	"REPLICAOF":             Keyword,
@@ -201,13 +244,19 @@ var matches = map[string]Type{
 	"SET-CONFIG-EPOCH":      Keyword,
 	"SETBIT":                Keyword,
 	"SETEX":                 Keyword,
+	"SETID":                 Keyword,
+	"SETINFO":               Keyword,
 	"SETNAME":               Keyword,
 	"SETNX":                 Keyword,
 	"SETRANGE":              Keyword,
 	"SETSLOT":               Keyword,
 	"SETUSER":               Keyword,
+	"SHARDCHANNELS":         Keyword,
+	"SHARDNUMSUB":           Keyword,
+	"SHARDS":                Keyword,
 	"SHUTDOWN":              Keyword,
 	"SINTER":                Keyword,
+	"SINTERCARD":            Keyword,
 	"SINTERSTORE":           Keyword,
 	"SISMEMBER":             Keyword,
 	"SLAVEOF":               Keyword,
@@ -218,16 +267,21 @@ var matches = map[string]Type{
 	"SMISMEMBER":            Keyword,
 	"SMOVE":                 Keyword,
 	"SORT":                  Keyword,
+	"SORT_RO":               Keyword,
 	"SPOP":                  Keyword,
+	"SPUBLISH":              Keyword,
 	"SRANDMEMBER":           Keyword,
 	"SREM":                  Keyword,
 	"SSCAN":                 Keyword,
+	"SSUBSCRIBE":            Keyword,
 	"STATS":                 Keyword,
 	"STRALGO":               Keyword,
+	"STREAM":                Keyword,
 	"STRLEN":                Keyword,
 	"SUBSCRIBE":             Keyword,
 	"SUNION":                Keyword,
 	"SUNIONSTORE":           Keyword,
+	"SUNSUBSCRIBE":          Keyword,
 	"SWAPDB":                Keyword,
 	"SYNC":                  Keyword,
 	"TIME":                  Keyword,
@@ -245,13 +299,14 @@ var matches = map[string]Type{
 	"USAGE":                 Keyword,
 	"USERS":                 Keyword,
 	"WAIT":                  Keyword,
+	"WAITAOF":               Keyword,
 	"WATCH":                 Keyword,
 	"WHOAMI":                Keyword,
 	"XACK":                  Keyword,
 	"XADD":                  Keyword,
 	"XAUTOCLAIM":            Keyword,
 	"XCLAIM":                Keyword,
-	"XDELID":                Keyword,
+	"XDEL":                  Keyword,
 	"XGROUP":                Keyword,
 	"XINFO":                 Keyword,
 	"XLEN":                  Keyword,
@@ -260,6 +315,7 @@ var matches = map[string]Type{
 	"XREAD":                 Keyword,
 	"XREADGROUP":            Keyword,
 	"XREVRANGE":             Keyword,
+	"XSETID":                Keyword,
 	"XTRIM":                 Keyword,
 	"ZADD":                  Keyword,
 	"ZCARD":                 Keyword,
@@ -268,8 +324,10 @@ var matches = map[string]Type{
 	"ZDIFFSTORE":            Keyword,
 	"ZINCRBY":               Keyword,
 	"ZINTER":                Keyword,
+	"ZINTERCARD":            Keyword,
 	"ZINTERSTORE":           Keyword,
 	"ZLEXCOUNT":             Keyword,
+	"ZMPOP":                 Keyword,
 	"ZMSCORE":               Keyword,
 	"ZPOPMAX":               Keyword,
 	"ZPOPMIN":               Keyword,
@@ -293,8 +351,9 @@ var matches = map[string]Type{
 	"ZUNIONSTORE":           Keyword,
 }
 
+// Match returns the type of the identifier, commands are case-insensitive.
 func Match(value string) Type {
-	_, ok := matches[value]
+	_, ok := matches[strings.ToUpper(value)]
 	if ok {
 		return Keyword
 	}
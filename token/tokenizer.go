@@ -1,7 +1,9 @@
 package token
 
 import (
+	"strings"
 	"unicode"
+	"unicode/utf8"
 )
 
 type Token struct {
@@ -9,6 +11,9 @@ type Token struct {
 	Start     int
 	End       int
 
+	// Line is the line where the token starts.
+	Line      int
+
 	// Since the LSP sends the cursor position based on the current line only
 	// we need to store the relative start and end of each token
 	// For example, if we are in the first position of the 3rd line we are on index 0 and not 2 (if the first two lines were empty).
@@ -26,12 +31,15 @@ func (t *Tokenizer) Tokenize(value string) []Token {
 
 	start := 0
 	lineStart := 0
+	line := 0
 	for {
 		token := t.nextToken(value, start, lineStart)
+		token.Line = line
 		tokens = append(tokens, token)
 
 		start = token.End + 1
 		lineStart = token.LineEnd + 1
+		line += strings.Count(token.Value, "\n")
 
 		if start == len(value) {
 			break
@@ -39,6 +47,7 @@ func (t *Tokenizer) Tokenize(value string) []Token {
 
 		if token.Type == Newline {
 			lineStart = 0
+			line++
 		}
 	}
 
@@ -54,10 +63,10 @@ func (*Tokenizer) nextToken(value string, start int, lineStart int) Token {
 			return Token{Start: start, End: start + i, LineStart: lineStart, LineEnd: lineStart + i, Type: Space, Value: " "}
 		case r == ';':
 			return Token{Start: start, End: start + i, LineStart: lineStart, LineEnd: lineStart + i, Type: Semicolon, Value: ";"}
-		case r == '"':
-			return nextString(value, start+i, lineStart+i)
-		case r == '\\':
-			continue
+		case r == '#' || strings.HasPrefix(value[start+i:], "//"):
+			return nextComment(value, start+i, lineStart+i)
+		case r == '"' || r == '\'':
+			return nextString(value, start+i, lineStart+i, r)
 		default:
 			return nextIdentifier(value, start+i, lineStart+i)
 		}
@@ -67,28 +76,94 @@ func (*Tokenizer) nextToken(value string, start int, lineStart int) Token {
 }
 
 func nextIdentifier(value string, start int, lineStart int) Token {
-	var identifier []rune
 	var end int
 	for i, r := range value[start:] {
-		if unicode.IsSpace(r) || r == ';' || r == '"' || r == '\\' {
+		if unicode.IsSpace(r) || r == ';' || r == '"' || r == '\'' {
 			break
 		}
-		identifier = append(identifier, r)
-		end = i
+		end = i + utf8.RuneLen(r) - 1
 	}
 
-	return Token{Start: start, End: start + end, LineStart: lineStart, LineEnd: lineStart + end, Type: Match(string(identifier)), Value: string(identifier)}
+	identifier := value[start : start+end+1]
+
+	return Token{Start: start, End: start + end, LineStart: lineStart, LineEnd: lineStart + end, Type: Match(identifier), Value: identifier}
 }
 
-func nextString(value string, start int, lineStart int) Token {
-	var identifier []rune
+// nextString returns a double or single quoted string following the redis-cli rules:
+// a quote can be escaped with a backslash and a string ends at the closing quote.
+// A string can have line breaks (e.g. an EVAL script) when its closing quote is followed by a separator,
+// otherwise it is not closed and ends at the end of its first line.
+// Strings that are not closed or whose closing quote is not followed by a space are returned as Illegal.
+func nextString(value string, start int, lineStart int, quote rune) Token {
 	var end int
+	escaped := false
+	closed := false
+	lineBreak := -1
 	for i, r := range value[start:] {
-		identifier = append(identifier, r)
-		end = i
-		if r == '"' && i != 0 {
+		if r == '\n' && lineBreak == -1 {
+			lineBreak = end
+		}
+
+		end = i + utf8.RuneLen(r) - 1
+		if i == 0 {
+			continue
+		}
+
+		if escaped {
+			escaped = false
+			continue
+		}
+
+		// single quoted strings only support escaping the quote itself
+		if r == '\\' && (quote == '"' || strings.HasPrefix(value[start+i+1:], "'")) {
+			escaped = true
+			continue
+		}
+
+		if r == quote {
+			closed = true
 			break
 		}
 	}
-	return Token{Start: start, End: start + end, LineStart: lineStart, LineEnd: lineStart + end, Type: String, Value: string(identifier)}
+
+	// a quote that is not followed by a separator is not the end of a string with line breaks
+	// but the start of another string after it (e.g. "SET a \"open\nSET b \"value\"")
+	next := start + end + 1
+	separated := next >= len(value) || isSeparator(rune(value[next]))
+	if lineBreak != -1 && (!closed || !separated) {
+		end = lineBreak
+		closed = false
+		next = start + end + 1
+	}
+
+	tokenType := String
+	if !closed || (next < len(value) && !isSeparator(rune(value[next]))) {
+		tokenType = Illegal
+	}
+
+	// the end of a string with line breaks is relative to its last line
+	lineEnd := lineStart + end
+	if newline := strings.LastIndexByte(value[start:next], '\n'); newline != -1 {
+		lineEnd = end - newline - 1
+	}
+
+	return Token{Start: start, End: start + end, LineStart: lineStart, LineEnd: lineEnd, Type: tokenType, Value: value[start:next]}
+}
+
+// nextComment returns a comment starting with # or // that goes until the end of the line.
+func nextComment(value string, start int, lineStart int) Token {
+	end := strings.IndexByte(value[start:], '\n')
+	if end == -1 {
+		end = len(value) - start
+	}
+
+	if end > 1 && value[start+end-1] == '\r' {
+		end--
+	}
+
+	return Token{Start: start, End: start + end - 1, LineStart: lineStart, LineEnd: lineStart + end - 1, Type: Comment, Value: value[start : start+end]}
+}
+
+func isSeparator(r rune) bool {
+	return unicode.IsSpace(r) || r == ';'
 }
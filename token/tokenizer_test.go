@@ -29,6 +29,60 @@ func TestTokenize(t *testing.T) {
 			4,
 			[]string{"GET", " ", "user", "<newline>"},
 		},
+		{
+			"String with escaped quote",
+			"SET test \"say \\\"hi\\\"\"",
+			5,
+			[]string{"SET", " ", "test", " ", "\"say \\\"hi\\\"\""},
+		},
+		{
+			"Single quoted string",
+			"SET test 'it\\'s'",
+			5,
+			[]string{"SET", " ", "test", " ", "'it\\'s'"},
+		},
+		{
+			"Backslash in identifier",
+			"GET a\\b",
+			3,
+			[]string{"GET", " ", "a\\b"},
+		},
+		{
+			"Hash comment",
+			"# seed data\nGET test",
+			5,
+			[]string{"# seed data", "<newline>", "GET", " ", "test"},
+		},
+		{
+			"Slash comment after command",
+			"GET test // read it",
+			5,
+			[]string{"GET", " ", "test", " ", "// read it"},
+		},
+		{
+			"Hash inside identifier",
+			"GET tag#1",
+			3,
+			[]string{"GET", " ", "tag#1"},
+		},
+		{
+			"Unterminated string ends at the newline",
+			"SET test \"open\nGET test",
+			9,
+			[]string{"SET", " ", "test", " ", "\"open", "<newline>", "GET", " ", "test"},
+		},
+		{
+			"String with line breaks",
+			"EVAL \"local a = 1\nreturn a\" 0\nGET a",
+			9,
+			[]string{"EVAL", " ", "\"local a = 1\nreturn a\"", " ", "0", "<newline>", "GET", " ", "a"},
+		},
+		{
+			"Unterminated string does not end at a later quote",
+			"SET \"abc\nGET x\nSET y \"z\"",
+			13,
+			[]string{"SET", " ", "\"abc", "<newline>", "GET", " ", "x", "<newline>", "SET", " ", "y", " ", "\"z\""},
+		},
 	}
 
 	for _, test := range tests {
@@ -99,4 +153,78 @@ func TestTokenize(t *testing.T) {
 			}
 		})
 	}
+
+	typeTests := []struct {
+		Name          string
+		Tokens        string
+		ExpectedTypes []Type
+	}{
+		{
+			"Uppercase command",
+			"SET foo bar",
+			[]Type{Keyword, Space, Unknown, Space, Unknown},
+		},
+		{
+			"Lowercase command",
+			"set foo bar",
+			[]Type{Keyword, Space, Unknown, Space, Unknown},
+		},
+		{
+			"Unterminated string",
+			"SET foo \"bar",
+			[]Type{Keyword, Space, Unknown, Space, Illegal},
+		},
+		{
+			"Closing quote followed by a character",
+			"SET foo \"bar\"baz",
+			[]Type{Keyword, Space, Unknown, Space, Illegal, Unknown},
+		},
+		{
+			"Single quoted string",
+			"SET foo 'bar'",
+			[]Type{Keyword, Space, Unknown, Space, String},
+		},
+	}
+
+	for _, test := range typeTests {
+		t.Run(test.Name, func(t *testing.T) {
+			tokenizer := Tokenizer{}
+			tokens := tokenizer.Tokenize(test.Tokens)
+
+			if len(tokens) != len(test.ExpectedTypes) {
+				t.Fatalf("%v - Unexpected amount of tokens: %v (expected %v)", test.Name, len(tokens), len(test.ExpectedTypes))
+			}
+
+			for i, token := range tokens {
+				if token.Type != test.ExpectedTypes[i] {
+					t.Errorf("%v - Unexpected type %v for token: %v (expected %v)", test.Name, token.Type, token.Value, test.ExpectedTypes[i])
+				}
+			}
+		})
+	}
+}
+
+func TestTokenizeLines(t *testing.T) {
+	tokenizer := Tokenizer{}
+	tokens := tokenizer.Tokenize("EVAL \"local a = 1\nreturn a\" 0\nGET a")
+
+	tests := []struct {
+		Index             int
+		ExpectedLine      int
+		ExpectedLineStart int
+		ExpectedLineEnd   int
+	}{
+		{0, 0, 0, 3},
+		{2, 0, 5, 8},
+		{4, 1, 10, 10},
+		{6, 2, 0, 2},
+	}
+
+	for _, test := range tests {
+		token := tokens[test.Index]
+		if token.Line != test.ExpectedLine || token.LineStart != test.ExpectedLineStart || token.LineEnd != test.ExpectedLineEnd {
+			t.Errorf("Unexpected position for token %v: line %v from %v to %v (expected line %v from %v to %v)",
+				token.Value, token.Line, token.LineStart, token.LineEnd, test.ExpectedLine, test.ExpectedLineStart, test.ExpectedLineEnd)
+		}
+	}
 }
@@ -16,11 +16,13 @@ func _() {
 	_ = x[Statement-5]
 	_ = x[MultiKeyword-6]
 	_ = x[Unknown-7]
+	_ = x[Illegal-8]
+	_ = x[Comment-9]
 }
 
-const _Type_name = "KeywordSpaceNewlineSemicolonStringStatementMultiKeywordUnknown"
+const _Type_name = "KeywordSpaceNewlineSemicolonStringStatementMultiKeywordUnknownIllegalComment"
 
-var _Type_index = [...]uint8{0, 7, 12, 19, 28, 34, 43, 55, 62}
+var _Type_index = [...]uint8{0, 7, 12, 19, 28, 34, 43, 55, 62, 69, 76}
 
 func (i Type) String() string {
 	if i < 0 || i >= Type(len(_Type_index)-1) {
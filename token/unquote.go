@@ -0,0 +1,167 @@
+package token
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+var (
+	ErrUnterminatedString = errors.New("unterminated quoted string")
+	ErrClosingQuote       = errors.New("closing quote must be followed by a space")
+)
+
+// Unquote returns the value Redis receives for a token, the same way redis-cli splits its arguments:
+// double quoted strings support the \n, \r, \t, \b, \a, \xHH and \<char> escapes,
+// single quoted strings only support \' and unquoted values are returned as they are.
+func Unquote(value string) (string, error) {
+	result, _, err := UnquoteOffsets(value)
+	if err != nil {
+		return "", err
+	}
+
+	return result, nil
+}
+
+// UnquoteOffsets is like Unquote but also returns the offset in the value each byte of the result comes from,
+// plus the offset of the closing quote, so positions in the result (e.g. in an EVAL script) can be mapped back to the document.
+// What could be unquoted is returned with the error when the string is not valid.
+func UnquoteOffsets(value string) (string, []int, error) {
+	if value == "" || (value[0] != '"' && value[0] != '\'') {
+		offsets := make([]int, len(value)+1)
+		for i := range offsets {
+			offsets[i] = i
+		}
+
+		return value, offsets, nil
+	}
+
+	quote := value[0]
+	var result strings.Builder
+	var offsets []int
+	write := func(c byte, offset int) {
+		result.WriteByte(c)
+		offsets = append(offsets, offset)
+	}
+
+	for i := 1; i < len(value); i++ {
+		c := value[i]
+
+		if c == quote {
+			offsets = append(offsets, i)
+			if i != len(value)-1 {
+				return result.String(), offsets, ErrClosingQuote
+			}
+
+			return result.String(), offsets, nil
+		}
+
+		if c != '\\' || i+1 >= len(value) {
+			write(c, i)
+			continue
+		}
+
+		next := value[i+1]
+		if quote == '\'' {
+			if next == '\'' {
+				write(next, i)
+				i++
+			} else {
+				write(c, i)
+			}
+
+			continue
+		}
+
+		escape := i
+		i++
+		switch next {
+		case 'n':
+			write('\n', escape)
+		case 'r':
+			write('\r', escape)
+		case 't':
+			write('\t', escape)
+		case 'b':
+			write('\b', escape)
+		case 'a':
+			write('\a', escape)
+		case 'x':
+			if i+2 < len(value) && isHex(value[i+1]) && isHex(value[i+2]) {
+				b, _ := strconv.ParseUint(value[i+1:i+3], 16, 8)
+				write(byte(b), escape)
+				i += 2
+			} else {
+				write(next, escape)
+			}
+		default:
+			write(next, escape)
+		}
+	}
+
+	offsets = append(offsets, len(value))
+
+	return result.String(), offsets, ErrUnterminatedString
+}
+
+// NeedsQuotes reports whether an unquoted value would not reach Redis as it is
+// (e.g. a value with a tab or starting with # that would be a comment).
+func NeedsQuotes(value string) bool {
+	if value == "" || strings.HasPrefix(value, "#") || strings.HasPrefix(value, "//") {
+		return true
+	}
+
+	return strings.IndexFunc(value, func(r rune) bool {
+		return unicode.IsSpace(r) || unicode.IsControl(r) || r == '"' || r == '\'' || r == ';'
+	}) != -1
+}
+
+// Quote returns the value as a double quoted string that Unquote turns back into the same value.
+func Quote(value string) string {
+	var result strings.Builder
+	result.WriteByte('"')
+	for _, r := range value {
+		switch r {
+		case '"', '\\':
+			result.WriteByte('\\')
+			result.WriteRune(r)
+		case '\n':
+			result.WriteString(`\n`)
+		case '\r':
+			result.WriteString(`\r`)
+		case '\t':
+			result.WriteString(`\t`)
+		case '\b':
+			result.WriteString(`\b`)
+		case '\a':
+			result.WriteString(`\a`)
+		default:
+			if r < 0x20 || r == 0x7f {
+				result.WriteString(fmt.Sprintf("\\x%02x", r))
+			} else {
+				result.WriteRune(r)
+			}
+		}
+	}
+
+	result.WriteByte('"')
+
+	return result.String()
+}
+
+// IllegalReason returns why the value of a token was tokenized as Illegal.
+func IllegalReason(value string) error {
+	_, err := Unquote(value)
+	if err != nil {
+		return err
+	}
+
+	// the string was closed but something other than a space came after the closing quote
+	return ErrClosingQuote
+}
+
+func isHex(c byte) bool {
+	return (c >= '0' && c <= '9') || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+}
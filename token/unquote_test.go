@@ -0,0 +1,95 @@
+package token
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestUnquote(t *testing.T) {
+	tests := []struct {
+		Name          string
+		Value         string
+		ExpectedValue string
+		ExpectedError error
+	}{
+		{"Unquoted value", "user:1", "user:1", nil},
+		{"Unquoted value with backslash", "a\\nb", "a\\nb", nil},
+		{"Double quoted string", "\"hello world\"", "hello world", nil},
+		{"Double quoted escapes", "\"a\\nb\\tc\\\"d\\\\e\"", "a\nb\tc\"d\\e", nil},
+		{"Double quoted hex escape", "\"\\x41\\x7a\"", "Az", nil},
+		{"Double quoted invalid hex escape", "\"\\xZZ\"", "xZZ", nil},
+		{"Single quoted string", "'a\\nb'", "a\\nb", nil},
+		{"Single quoted escaped quote", "'it\\'s'", "it's", nil},
+		{"Unterminated double quoted string", "\"open", "", ErrUnterminatedString},
+		{"Unterminated single quoted string", "'open", "", ErrUnterminatedString},
+		{"Closing quote followed by a character", "\"a\"b", "", ErrClosingQuote},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			value, err := Unquote(test.Value)
+			if err != test.ExpectedError {
+				t.Fatalf("%v - Unexpected error: %v (expected %v)", test.Name, err, test.ExpectedError)
+			}
+
+			if value != test.ExpectedValue {
+				t.Errorf("%v - Unexpected value: %q (expected %q)", test.Name, value, test.ExpectedValue)
+			}
+		})
+	}
+}
+
+func TestQuote(t *testing.T) {
+	tests := []struct {
+		Name          string
+		Value         string
+		ExpectedValue string
+	}{
+		{"Plain value", "hello", "\"hello\""},
+		{"Value with tab", "a\tb", "\"a\\tb\""},
+		{"Value with quote and backslash", "a\"b\\c", "\"a\\\"b\\\\c\""},
+		{"Value with control character", "a\x01b", "\"a\\x01b\""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			quoted := Quote(test.Value)
+			if quoted != test.ExpectedValue {
+				t.Errorf("expected quoted value %v, got %v", test.ExpectedValue, quoted)
+			}
+
+			value, err := Unquote(quoted)
+			if err != nil || value != test.Value {
+				t.Errorf("expected unquoted value %q, got %q (%v)", test.Value, value, err)
+			}
+		})
+	}
+}
+
+func TestUnquoteOffsets(t *testing.T) {
+	tests := []struct {
+		Name            string
+		Value           string
+		ExpectedValue   string
+		ExpectedOffsets []int
+	}{
+		{"Unquoted value", "ab", "ab", []int{0, 1, 2}},
+		{"Double quoted escapes", "\"a\\nb\"", "a\nb", []int{1, 2, 4, 5}},
+		{"Double quoted hex escape", "\"\\x41b\"", "Ab", []int{1, 5, 6}},
+		{"Single quoted escaped quote", "'\\'a'", "'a", []int{1, 3, 4}},
+		{"Unterminated string", "\"ab", "ab", []int{1, 2, 3}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			value, offsets, _ := UnquoteOffsets(test.Value)
+			if value != test.ExpectedValue {
+				t.Errorf("%v - Unexpected value: %q (expected %q)", test.Name, value, test.ExpectedValue)
+			}
+
+			if !reflect.DeepEqual(offsets, test.ExpectedOffsets) {
+				t.Errorf("%v - Unexpected offsets: %v (expected %v)", test.Name, offsets, test.ExpectedOffsets)
+			}
+		})
+	}
+}
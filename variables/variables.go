@@ -0,0 +1,203 @@
+package variables
+
+import (
+	"bufio"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/fagnercarvalho/redis-lsp/ast"
+	"github.com/fagnercarvalho/redis-lsp/token"
+)
+
+// Directive is the statement that defines a variable in a file (e.g. "@set tenant = acme").
+const Directive = "@SET"
+
+type Source string
+
+const (
+	File        Source = "file"
+	EnvFile     Source = "env file"
+	Environment Source = "environment"
+)
+
+type Variable struct {
+	Name   string
+	Value  string
+	Source Source
+
+	// Line is where the variable was defined when the source is a file.
+	Line int
+}
+
+// Reference is a ${NAME} placeholder inside a value, Start and End are the offsets in the value (End is exclusive).
+type Reference struct {
+	Name  string
+	Start int
+	End   int
+}
+
+var referencePattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// IsDirective reports whether the statement defines a variable instead of being a command.
+func IsDirective(s ast.TokenList) bool {
+	return ast.CommandName(s) == Directive
+}
+
+// Definitions returns the variables defined in the statements, in the order they were defined.
+// Both "@set name = value" and "@set name value" are accepted.
+func Definitions(statements []ast.TokenList) []Variable {
+	var variables []Variable
+	for _, s := range statements {
+		if !IsDirective(s) {
+			continue
+		}
+
+		arguments := s.GetArguments()
+		if len(arguments) == 1 && strings.Contains(arguments[0].String(), "=") {
+			// "@set name=value" is a single argument
+			parts := strings.SplitN(arguments[0].String(), "=", 2)
+			variables = append(variables, Variable{Name: parts[0], Value: parts[1], Source: File, Line: s.Line()})
+			continue
+		}
+
+		if len(arguments) >= 2 && arguments[1].String() == "=" {
+			arguments = append(arguments[:1], arguments[2:]...)
+		}
+
+		if len(arguments) < 2 {
+			continue
+		}
+
+		value, err := token.Unquote(arguments[1].String())
+		if err != nil {
+			continue
+		}
+
+		variables = append(variables, Variable{Name: arguments[0].String(), Value: value, Source: File, Line: s.Line()})
+	}
+
+	return variables
+}
+
+// References returns every ${NAME} placeholder in the value.
+func References(value string) []Reference {
+	var references []Reference
+	for _, match := range referencePattern.FindAllStringSubmatchIndex(value, -1) {
+		references = append(references, Reference{Name: value[match[2]:match[3]], Start: match[0], End: match[1]})
+	}
+
+	return references
+}
+
+// Resolver finds the value of a variable looking first at the file definitions,
+// then at the env file and finally at the process environment.
+type Resolver struct {
+	Definitions []Variable
+	EnvFile     map[string]string
+}
+
+// Lookup returns the variable as seen from the given line, definitions after that line are not visible.
+func (r Resolver) Lookup(name string, line int) (Variable, bool) {
+	var found *Variable
+	for i, v := range r.Definitions {
+		if v.Name == name && v.Line < line {
+			found = &r.Definitions[i]
+		}
+	}
+
+	if found != nil {
+		return *found, true
+	}
+
+	if value, ok := r.EnvFile[name]; ok {
+		return Variable{Name: name, Value: value, Source: EnvFile}, true
+	}
+
+	if value, ok := os.LookupEnv(name); ok {
+		return Variable{Name: name, Value: value, Source: Environment}, true
+	}
+
+	return Variable{}, false
+}
+
+// Resolve replaces every placeholder in the value and returns the names of the variables that could not be resolved.
+func (r Resolver) Resolve(value string, line int) (string, []string) {
+	var unresolved []string
+	resolved := referencePattern.ReplaceAllStringFunc(value, func(placeholder string) string {
+		name := placeholder[2 : len(placeholder)-1]
+		variable, ok := r.Lookup(name, line)
+		if !ok {
+			unresolved = append(unresolved, name)
+			return placeholder
+		}
+
+		if variable.Source == File {
+			// definitions can use variables defined before them
+			value, _ := r.Resolve(variable.Value, variable.Line)
+			return value
+		}
+
+		return variable.Value
+	})
+
+	return resolved, unresolved
+}
+
+// Names returns the names of the variables defined in the file and in the env file.
+func (r Resolver) Names() []string {
+	var names []string
+	seen := map[string]bool{}
+	for _, v := range r.Definitions {
+		if !seen[v.Name] {
+			seen[v.Name] = true
+			names = append(names, v.Name)
+		}
+	}
+
+	var envNames []string
+	for name := range r.EnvFile {
+		if !seen[name] {
+			envNames = append(envNames, name)
+		}
+	}
+
+	sort.Strings(envNames)
+
+	return append(names, envNames...)
+}
+
+// LoadEnvFile reads a file with a NAME=value definition per line.
+// Empty lines and lines starting with # are ignored and values can be quoted.
+func LoadEnvFile(path string) (map[string]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	variables := map[string]string{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		line = strings.TrimPrefix(line, "export ")
+		parts := strings.SplitN(line, "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+
+		value := strings.TrimSpace(parts[1])
+		if unquoted, err := token.Unquote(value); err == nil {
+			value = unquoted
+		}
+
+		variables[strings.TrimSpace(parts[0])] = value
+	}
+
+	return variables, scanner.Err()
+}
@@ -0,0 +1,95 @@
+package variables
+
+import (
+	"testing"
+
+	"github.com/fagnercarvalho/redis-lsp/ast"
+)
+
+func TestResolve(t *testing.T) {
+	tests := []struct {
+		Name               string
+		Statements         string
+		EnvFile            map[string]string
+		Value              string
+		Line               int
+		ExpectedValue      string
+		ExpectedUnresolved []string
+	}{
+		{
+			"Variable defined in file",
+			"@set tenant = acme\nGET ${tenant}:users",
+			nil,
+			"${tenant}:users",
+			1,
+			"acme:users",
+			nil,
+		},
+		{
+			"Variable defined without equal sign",
+			"@set tenant \"big corp\"\nGET ${tenant}",
+			nil,
+			"${tenant}",
+			1,
+			"big corp",
+			nil,
+		},
+		{
+			"Variable defined after the line is not visible",
+			"GET ${tenant}\n@set tenant = acme",
+			nil,
+			"${tenant}",
+			0,
+			"${tenant}",
+			[]string{"tenant"},
+		},
+		{
+			"File definition overrides env file",
+			"@set tenant = acme\nGET ${tenant}",
+			map[string]string{"tenant": "other"},
+			"${tenant}",
+			1,
+			"acme",
+			nil,
+		},
+		{
+			"Variable from env file",
+			"GET ${TENANT}",
+			map[string]string{"TENANT": "globex"},
+			"user:${TENANT}:1",
+			0,
+			"user:globex:1",
+			nil,
+		},
+		{
+			"Definition using another variable",
+			"@set tenant = acme\n@set prefix = ${tenant}:cache\nGET ${prefix}",
+			nil,
+			"${prefix}:1",
+			2,
+			"acme:cache:1",
+			nil,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			resolver := Resolver{Definitions: Definitions(ast.Parse(test.Statements)), EnvFile: test.EnvFile}
+			value, unresolved := resolver.Resolve(test.Value, test.Line)
+
+			if value != test.ExpectedValue {
+				t.Errorf("%v - Unexpected value: %v (expected %v)", test.Name, value, test.ExpectedValue)
+			}
+
+			if len(unresolved) != len(test.ExpectedUnresolved) {
+				t.Fatalf("%v - Unexpected unresolved variables: %v (expected %v)", test.Name, unresolved, test.ExpectedUnresolved)
+			}
+
+			for i, name := range unresolved {
+				if name != test.ExpectedUnresolved[i] {
+					t.Errorf("%v - Unexpected unresolved variable: %v (expected %v)", test.Name, name, test.ExpectedUnresolved[i])
+				}
+			}
+		})
+	}
+}